@@ -112,5 +112,7 @@ func (c *Client) GetActivity(ctx context.Context, params *GetActivityParams) ([]
 		return nil, fmt.Errorf("failed to decode activity response: %w", err)
 	}
 
+	c.Directory().ObserveActivity(activities)
+
 	return activities, nil
 }
@@ -0,0 +1,80 @@
+// Package arbitrage checks prices derived from recent trades for
+// inconsistencies that imply an arbitrage or a data-quality problem.
+package arbitrage
+
+import (
+	"sort"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultMaxAge is the age after which a trade price is considered stale
+const DefaultMaxAge = 10 * time.Minute
+
+// DefaultTradeLimit is the number of recent trades fetched per market
+const DefaultTradeLimit = 20
+
+var one = decimal.NewFromInt(1)
+
+// Quote is the latest traded price of an outcome token
+type Quote struct {
+	Asset        string
+	OutcomeIndex int
+	Outcome      string
+	Price        decimal.Decimal
+	Timestamp    int64
+	Age          time.Duration // Time between the trade and the check
+	Stale        bool          // Age exceeds the maximum age
+	Trade        polymarketdata.Trade
+}
+
+// latestTrades returns the latest trade of every asset, by asset. For equal
+// timestamps the trade listed first wins, as the API lists the newest first.
+func latestTrades(trades []polymarketdata.Trade) map[string]polymarketdata.Trade {
+	out := make(map[string]polymarketdata.Trade)
+	for _, t := range trades {
+		if cur, ok := out[t.Asset]; !ok || t.Timestamp > cur.Timestamp {
+			out[t.Asset] = t
+		}
+	}
+	return out
+}
+
+// quote builds a quote from a trade
+func quote(t polymarketdata.Trade, now time.Time, maxAge time.Duration) Quote {
+	age := now.Sub(time.Unix(t.Timestamp, 0))
+	if age < 0 {
+		age = 0
+	}
+	return Quote{
+		Asset:        t.Asset,
+		OutcomeIndex: t.OutcomeIndex,
+		Outcome:      t.Outcome,
+		Price:        t.Price,
+		Timestamp:    t.Timestamp,
+		Age:          age,
+		Stale:        age > maxAge,
+		Trade:        t,
+	}
+}
+
+// byCondition groups trades by condition ID, keeping the first-seen order of conditions
+func byCondition(trades []polymarketdata.Trade) ([]string, map[string][]polymarketdata.Trade) {
+	var order []string
+	out := make(map[string][]polymarketdata.Trade)
+	for _, t := range trades {
+		if _, ok := out[t.ConditionId]; !ok {
+			order = append(order, t.ConditionId)
+		}
+		out[t.ConditionId] = append(out[t.ConditionId], t)
+	}
+	return order, out
+}
+
+func sortTrades(trades []polymarketdata.Trade) {
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Timestamp < trades[j].Timestamp
+	})
+}
@@ -0,0 +1,180 @@
+package arbitrage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// Direction is the side of a negative-risk arbitrage
+type Direction string
+
+const (
+	DirectionNone   Direction = ""
+	DirectionBuyYes Direction = "BUY_YES" // YES prices sum to less than 1: a YES in every market costs the sum and pays 1
+	DirectionBuyNo  Direction = "BUY_NO"  // YES prices sum to more than 1: a NO in every market costs n - sum and pays n - 1
+)
+
+// NegRiskOptions configures a NegRiskScanner
+type NegRiskOptions struct {
+	Threshold  decimal.Decimal // Optional: Minimum edge for an opportunity. Default 0.02
+	MaxAge     time.Duration   // Optional: Prices from older trades are stale. Default DefaultMaxAge
+	TradeLimit int             // Optional: Recent trades fetched per market. Default DefaultTradeLimit
+}
+
+// MarketPrice is the YES price of one market of an event
+type MarketPrice struct {
+	ConditionId string
+	Title       string
+	Price       decimal.Decimal // YES price
+	FromNo      bool            // Derived as 1 - price from a trade of the NO token
+	Quote       Quote           // The latest trade of either token
+}
+
+// NegRiskResult is the outcome of checking the markets of a negative-risk event,
+// whose outcomes are mutually exclusive so that their YES prices should sum to 1
+type NegRiskResult struct {
+	EventId     int // Zero unless scanned by event
+	CheckedAt   time.Time
+	Markets     []MarketPrice // In the order the markets were given or first seen
+	Missing     []string      // Markets without recent trades
+	Sum         decimal.Decimal
+	Deviation   decimal.Decimal // Sum - 1
+	Edge        decimal.Decimal // Profit per set before fees and slippage: |Sum - 1|
+	Direction   Direction
+	Opportunity bool // Edge exceeds the threshold, every market has a price and none is stale
+	Stale       int  // Markets with a stale price
+	OldestAge   time.Duration
+	NewestAge   time.Duration
+	Trades      []polymarketdata.Trade // The trades the prices were derived from, oldest first
+}
+
+// NegRiskScanner checks negative-risk events for YES prices that do not sum to 1
+type NegRiskScanner struct {
+	client *polymarketdata.Client
+	opts   NegRiskOptions
+	now    func() time.Time
+}
+
+// NewNegRiskScanner creates a scanner
+func NewNegRiskScanner(client *polymarketdata.Client, opts *NegRiskOptions) (*NegRiskScanner, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	s := &NegRiskScanner{client: client, now: time.Now}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.TradeLimit <= 0 {
+		s.opts.TradeLimit = DefaultTradeLimit
+	}
+	return s, nil
+}
+
+// ScanMarkets fetches recent trades of every market of an event and checks their prices.
+// Markets are fetched one by one so that quiet markets are not crowded out by busy ones.
+func (s *NegRiskScanner) ScanMarkets(ctx context.Context, markets []string) (*NegRiskResult, error) {
+	if len(markets) < 2 {
+		return nil, fmt.Errorf("at least two markets are required")
+	}
+	var trades []polymarketdata.Trade
+	for _, market := range markets {
+		page, err := s.client.GetTrades(ctx, &polymarketdata.GetTradesParams{Market: []string{market}, Limit: s.opts.TradeLimit})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch trades of %s: %w", market, err)
+		}
+		trades = append(trades, page...)
+	}
+	return EvaluateNegRisk(markets, trades, s.now(), &s.opts)
+}
+
+// ScanEvent fetches an event's recent trades and checks the prices of the markets
+// that appear in them. Markets without trades in the fetched page are not seen,
+// so prefer ScanMarkets when the event's markets are known.
+func (s *NegRiskScanner) ScanEvent(ctx context.Context, eventId int) (*NegRiskResult, error) {
+	trades, err := s.client.GetTrades(ctx, &polymarketdata.GetTradesParams{EventId: []int{eventId}, Limit: s.opts.TradeLimit * 10})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trades of event %d: %w", eventId, err)
+	}
+	result, err := EvaluateNegRisk(nil, trades, s.now(), &s.opts)
+	if err != nil {
+		return nil, err
+	}
+	result.EventId = eventId
+	return result, nil
+}
+
+// EvaluateNegRisk derives the YES price of every market from its latest trade and
+// checks whether they sum to 1. A trade of the NO token (outcome index 1) at price p
+// implies a YES price of 1 - p. If markets is nil, the markets seen in trades are used.
+func EvaluateNegRisk(markets []string, trades []polymarketdata.Trade, now time.Time, opts *NegRiskOptions) (*NegRiskResult, error) {
+	threshold := decimal.RequireFromString("0.02")
+	maxAge := DefaultMaxAge
+	if opts != nil {
+		if opts.Threshold.IsPositive() {
+			threshold = opts.Threshold
+		}
+		if opts.MaxAge > 0 {
+			maxAge = opts.MaxAge
+		}
+	}
+
+	seen, grouped := byCondition(trades)
+	if markets == nil {
+		markets = seen
+	}
+	if len(markets) == 0 {
+		return nil, errors.New("no markets to check")
+	}
+
+	r := &NegRiskResult{CheckedAt: now}
+	for _, market := range markets {
+		var latest *polymarketdata.Trade
+		for _, t := range latestTrades(grouped[market]) {
+			if latest == nil || t.Timestamp > latest.Timestamp || (t.Timestamp == latest.Timestamp && t.OutcomeIndex < latest.OutcomeIndex) {
+				latest = &t
+			}
+		}
+		if latest == nil {
+			r.Missing = append(r.Missing, market)
+			continue
+		}
+
+		q := quote(*latest, now, maxAge)
+		mp := MarketPrice{ConditionId: market, Title: latest.Title, Price: q.Price, Quote: q}
+		if latest.OutcomeIndex != 0 {
+			mp.Price = one.Sub(q.Price)
+			mp.FromNo = true
+		}
+		r.Markets = append(r.Markets, mp)
+		r.Trades = append(r.Trades, *latest)
+		r.Sum = r.Sum.Add(mp.Price)
+		if q.Stale {
+			r.Stale++
+		}
+		if len(r.Markets) == 1 || q.Age > r.OldestAge {
+			r.OldestAge = q.Age
+		}
+		if len(r.Markets) == 1 || q.Age < r.NewestAge {
+			r.NewestAge = q.Age
+		}
+	}
+	sortTrades(r.Trades)
+
+	if len(r.Missing) == 0 {
+		r.Deviation = r.Sum.Sub(one)
+		r.Edge = r.Deviation.Abs()
+		switch r.Deviation.Sign() {
+		case -1:
+			r.Direction = DirectionBuyYes
+		case 1:
+			r.Direction = DirectionBuyNo
+		}
+		r.Opportunity = r.Edge.GreaterThan(threshold) && r.Stale == 0
+	}
+	return r, nil
+}
@@ -0,0 +1,130 @@
+package arbitrage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *polymarketdata.Client {
+	t.Helper()
+	client, err := polymarketdata.NewClient(&http.Client{Transport: handlerTransport{handler}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func trade(condition string, outcomeIndex int, ts int64, price string) polymarketdata.Trade {
+	return polymarketdata.Trade{
+		ConditionId:  condition,
+		Asset:        condition + "-" + []string{"yes", "no"}[outcomeIndex],
+		OutcomeIndex: outcomeIndex,
+		Timestamp:    ts,
+		Size:         decimal.NewFromInt(10),
+		Price:        decimal.RequireFromString(price),
+	}
+}
+
+func TestEvaluateNegRisk(t *testing.T) {
+	now := time.Unix(1000, 0)
+	trades := []polymarketdata.Trade{
+		trade("0xa", 0, 990, "0.50"),
+		trade("0xa", 0, 900, "0.40"),
+		trade("0xb", 1, 980, "0.75"), // YES at 0.25
+		trade("0xb", 0, 950, "0.20"),
+		trade("0xc", 0, 970, "0.20"),
+	}
+
+	r, err := EvaluateNegRisk([]string{"0xa", "0xb", "0xc"}, trades, now, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Sum.Equal(decimal.RequireFromString("0.95")) || !r.Edge.Equal(decimal.RequireFromString("0.05")) {
+		t.Errorf("unexpected sum %s and edge %s", r.Sum, r.Edge)
+	}
+	if r.Direction != DirectionBuyYes || !r.Opportunity {
+		t.Errorf("expected a YES opportunity, got %+v", r)
+	}
+	if !r.Markets[1].FromNo || !r.Markets[1].Price.Equal(decimal.RequireFromString("0.25")) {
+		t.Errorf("expected the NO trade to imply 0.25, got %+v", r.Markets[1])
+	}
+	if r.OldestAge != 30*time.Second || r.NewestAge != 10*time.Second {
+		t.Errorf("unexpected ages %s and %s", r.OldestAge, r.NewestAge)
+	}
+	if len(r.Trades) != 3 || r.Trades[0].ConditionId != "0xc" {
+		t.Errorf("expected the supporting trades oldest first, got %+v", r.Trades)
+	}
+
+	// A stale price spoils the opportunity
+	r, err = EvaluateNegRisk([]string{"0xa", "0xb", "0xc"}, trades, now, &NegRiskOptions{MaxAge: 20 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Stale != 1 || r.Opportunity {
+		t.Errorf("expected one stale market and no opportunity, got %+v", r)
+	}
+
+	// So does an edge below the threshold or a missing market
+	r, err = EvaluateNegRisk([]string{"0xa", "0xb", "0xc"}, trades, now, &NegRiskOptions{Threshold: decimal.RequireFromString("0.1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Opportunity {
+		t.Errorf("expected no opportunity below the threshold")
+	}
+	r, err = EvaluateNegRisk([]string{"0xa", "0xb", "0xc", "0xd"}, trades, now, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Missing) != 1 || r.Missing[0] != "0xd" || r.Opportunity || r.Direction != DirectionNone {
+		t.Errorf("expected 0xd to be missing, got %+v", r)
+	}
+}
+
+func TestNegRiskScanner(t *testing.T) {
+	byMarket := map[string][]polymarketdata.Trade{
+		"0xa": {trade("0xa", 0, 990, "0.60")},
+		"0xb": {trade("0xb", 0, 995, "0.45")},
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "20" {
+			t.Errorf("unexpected limit %q", r.URL.Query().Get("limit"))
+		}
+		json.NewEncoder(w).Encode(byMarket[r.URL.Query().Get("market")])
+	})
+
+	scanner, err := NewNegRiskScanner(client, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scanner.now = func() time.Time { return time.Unix(1000, 0) }
+
+	r, err := scanner.ScanMarkets(context.Background(), []string{"0xa", "0xb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Direction != DirectionBuyNo || !r.Edge.Equal(decimal.RequireFromString("0.05")) || !r.Opportunity {
+		t.Errorf("expected a NO opportunity, got %+v", r)
+	}
+
+	if _, err := scanner.ScanMarkets(context.Background(), []string{"0xa"}); err == nil {
+		t.Error("expected an error for a single market")
+	}
+}
@@ -0,0 +1,212 @@
+package arbitrage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultHistory is the number of parity points kept per condition
+const DefaultHistory = 1000
+
+// ParityOptions configures parity checks
+type ParityOptions struct {
+	Tolerance  decimal.Decimal // Optional: Maximum deviation of YES + NO from 1. Default 0.02
+	MaxAge     time.Duration   // Optional: Prices from older trades are stale. Default DefaultMaxAge
+	TradeLimit int             // Optional: Recent trades fetched per market. Default DefaultTradeLimit
+	History    int             // Optional: Points kept per condition by a ParityChecker. Default DefaultHistory
+}
+
+// Parity pairs the last traded prices of both tokens of a binary condition
+type Parity struct {
+	ConditionId string
+	Title       string
+	CheckedAt   time.Time
+	Yes         Quote // Outcome index 0
+	No          Quote // Outcome index 1
+	Sum         decimal.Decimal
+	Deviation   decimal.Decimal // Sum - 1
+	Gap         time.Duration   // Time between the two trades
+	Stale       bool            // Either price is stale
+	Flagged     bool            // The deviation exceeds the tolerance and neither price is stale
+}
+
+// ParityPoint is one observation of a condition's deviation
+type ParityPoint struct {
+	Timestamp time.Time
+	Sum       decimal.Decimal
+	Deviation decimal.Decimal
+	Stale     bool
+	Flagged   bool
+}
+
+func (p Parity) point() ParityPoint {
+	return ParityPoint{Timestamp: p.CheckedAt, Sum: p.Sum, Deviation: p.Deviation, Stale: p.Stale, Flagged: p.Flagged}
+}
+
+type parityConfig struct {
+	tolerance decimal.Decimal
+	maxAge    time.Duration
+}
+
+func newParityConfig(opts *ParityOptions) parityConfig {
+	cfg := parityConfig{tolerance: decimal.RequireFromString("0.02"), maxAge: DefaultMaxAge}
+	if opts != nil {
+		if opts.Tolerance.IsPositive() {
+			cfg.tolerance = opts.Tolerance
+		}
+		if opts.MaxAge > 0 {
+			cfg.maxAge = opts.MaxAge
+		}
+	}
+	return cfg
+}
+
+// pair evaluates the latest YES and NO trades of a condition
+func (cfg parityConfig) pair(yes, no polymarketdata.Trade, now time.Time) Parity {
+	p := Parity{
+		ConditionId: yes.ConditionId,
+		Title:       yes.Title,
+		CheckedAt:   now,
+		Yes:         quote(yes, now, cfg.maxAge),
+		No:          quote(no, now, cfg.maxAge),
+	}
+	p.Sum = p.Yes.Price.Add(p.No.Price)
+	p.Deviation = p.Sum.Sub(one)
+	p.Gap = time.Duration(yes.Timestamp-no.Timestamp) * time.Second
+	if p.Gap < 0 {
+		p.Gap = -p.Gap
+	}
+	p.Stale = p.Yes.Stale || p.No.Stale
+	p.Flagged = p.Deviation.Abs().GreaterThan(cfg.tolerance) && !p.Stale
+	return p
+}
+
+// binaryLatest returns the latest YES and NO trades of a condition,
+// or false if either side has not traded or the condition is not binary
+func binaryLatest(trades []polymarketdata.Trade) (yes, no polymarketdata.Trade, ok bool) {
+	var haveYes, haveNo bool
+	for _, t := range latestTrades(trades) {
+		switch t.OutcomeIndex {
+		case 0:
+			if !haveYes || t.Timestamp > yes.Timestamp {
+				yes, haveYes = t, true
+			}
+		case 1:
+			if !haveNo || t.Timestamp > no.Timestamp {
+				no, haveNo = t, true
+			}
+		default:
+			return yes, no, false
+		}
+	}
+	return yes, no, haveYes && haveNo
+}
+
+// CheckParity pairs the last traded prices of both tokens of every binary
+// condition in trades. Conditions where only one token traded are skipped.
+func CheckParity(trades []polymarketdata.Trade, now time.Time, opts *ParityOptions) []Parity {
+	cfg := newParityConfig(opts)
+	order, grouped := byCondition(trades)
+	var out []Parity
+	for _, conditionId := range order {
+		if yes, no, ok := binaryLatest(grouped[conditionId]); ok {
+			out = append(out, cfg.pair(yes, no, now))
+		}
+	}
+	return out
+}
+
+// ReplayParity replays trades in timestamp order and records a point for
+// every trade after which both tokens of its condition have a price. The age
+// of the other token's price at that moment decides staleness.
+func ReplayParity(trades []polymarketdata.Trade, opts *ParityOptions) map[string][]ParityPoint {
+	cfg := newParityConfig(opts)
+	sorted := append([]polymarketdata.Trade(nil), trades...)
+	sortTrades(sorted)
+
+	last := make(map[string]*[2]*polymarketdata.Trade)
+	out := make(map[string][]ParityPoint)
+	for i := range sorted {
+		t := &sorted[i]
+		if t.OutcomeIndex != 0 && t.OutcomeIndex != 1 {
+			continue
+		}
+		sides, ok := last[t.ConditionId]
+		if !ok {
+			sides = new([2]*polymarketdata.Trade)
+			last[t.ConditionId] = sides
+		}
+		sides[t.OutcomeIndex] = t
+		if sides[0] == nil || sides[1] == nil {
+			continue
+		}
+		p := cfg.pair(*sides[0], *sides[1], time.Unix(t.Timestamp, 0))
+		out[t.ConditionId] = append(out[t.ConditionId], p.point())
+	}
+	return out
+}
+
+// ParityChecker periodically checks binary conditions and keeps the deviation of each as a time series
+type ParityChecker struct {
+	client *polymarketdata.Client
+	opts   ParityOptions
+	now    func() time.Time
+
+	mu     sync.Mutex
+	series map[string][]ParityPoint
+}
+
+// NewParityChecker creates a parity checker
+func NewParityChecker(client *polymarketdata.Client, opts *ParityOptions) (*ParityChecker, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	c := &ParityChecker{client: client, now: time.Now, series: make(map[string][]ParityPoint)}
+	if opts != nil {
+		c.opts = *opts
+	}
+	if c.opts.TradeLimit <= 0 {
+		c.opts.TradeLimit = DefaultTradeLimit
+	}
+	if c.opts.History <= 0 {
+		c.opts.History = DefaultHistory
+	}
+	return c, nil
+}
+
+// Check fetches recent trades of every market, checks their parity and records
+// the results. Markets where only one token traded recently are left out.
+func (c *ParityChecker) Check(ctx context.Context, markets []string) ([]Parity, error) {
+	var trades []polymarketdata.Trade
+	for _, market := range markets {
+		page, err := c.client.GetTrades(ctx, &polymarketdata.GetTradesParams{Market: []string{market}, Limit: c.opts.TradeLimit})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch trades of %s: %w", market, err)
+		}
+		trades = append(trades, page...)
+	}
+	results := CheckParity(trades, c.now(), &c.opts)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, p := range results {
+		s := append(c.series[p.ConditionId], p.point())
+		if len(s) > c.opts.History {
+			s = s[len(s)-c.opts.History:]
+		}
+		c.series[p.ConditionId] = s
+	}
+	return results, nil
+}
+
+// Series returns the recorded points of a condition, oldest first
+func (c *ParityChecker) Series(conditionId string) []ParityPoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ParityPoint(nil), c.series[conditionId]...)
+}
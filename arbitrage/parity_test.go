@@ -0,0 +1,107 @@
+package arbitrage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func TestCheckParity(t *testing.T) {
+	now := time.Unix(1000, 0)
+	trades := []polymarketdata.Trade{
+		trade("0xa", 0, 990, "0.60"),
+		trade("0xa", 1, 980, "0.45"),
+		trade("0xa", 0, 900, "0.55"),
+		trade("0xb", 0, 990, "0.30"),
+		trade("0xb", 1, 100, "0.60"), // Stale
+		trade("0xc", 0, 995, "0.50"), // No NO trade
+	}
+
+	results := CheckParity(trades, now, nil)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %+v", results)
+	}
+
+	a := results[0]
+	if a.ConditionId != "0xa" || !a.Deviation.Equal(decimal.RequireFromString("0.05")) || !a.Flagged {
+		t.Errorf("expected 0xa to be flagged at 0.05, got %+v", a)
+	}
+	if a.Gap != 10*time.Second || a.Yes.Age != 10*time.Second {
+		t.Errorf("unexpected gap %s and age %s", a.Gap, a.Yes.Age)
+	}
+
+	b := results[1]
+	if !b.Stale || b.Flagged || !b.Deviation.Equal(decimal.RequireFromString("-0.1")) {
+		t.Errorf("expected 0xb to be stale and not flagged, got %+v", b)
+	}
+
+	results = CheckParity(trades, now, &ParityOptions{Tolerance: decimal.RequireFromString("0.1")})
+	if results[0].Flagged {
+		t.Errorf("expected 0xa within a 0.1 tolerance")
+	}
+}
+
+func TestReplayParity(t *testing.T) {
+	series := ReplayParity([]polymarketdata.Trade{
+		trade("0xa", 0, 300, "0.52"),
+		trade("0xa", 0, 100, "0.50"),
+		trade("0xa", 1, 200, "0.50"),
+		trade("0xa", 1, 1200, "0.47"),
+	}, &ParityOptions{MaxAge: 5 * time.Minute})
+
+	points := series["0xa"]
+	if len(points) != 3 {
+		t.Fatalf("expected 3 points, got %+v", points)
+	}
+	want := []struct {
+		ts        int64
+		deviation string
+		stale     bool
+	}{
+		{200, "0", false},
+		{300, "0.02", false},
+		{1200, "-0.01", true},
+	}
+	for i, w := range want {
+		p := points[i]
+		if p.Timestamp.Unix() != w.ts || !p.Deviation.Equal(decimal.RequireFromString(w.deviation)) || p.Stale != w.stale {
+			t.Errorf("point %d: got %+v, want %+v", i, p, w)
+		}
+	}
+}
+
+func TestParityChecker(t *testing.T) {
+	price := "0.45"
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]polymarketdata.Trade{
+			trade("0xa", 0, 990, "0.60"),
+			trade("0xa", 1, 980, price),
+		})
+	})
+
+	checker, err := NewParityChecker(client, &ParityOptions{History: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker.now = func() time.Time { return time.Unix(1000, 0) }
+
+	for _, p := range []string{"0.45", "0.40", "0.41"} {
+		price = p
+		if _, err := checker.Check(context.Background(), []string{"0xa"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	series := checker.Series("0xa")
+	if len(series) != 2 {
+		t.Fatalf("expected the history to be capped at 2, got %d", len(series))
+	}
+	if !series[0].Deviation.IsZero() || !series[1].Deviation.Equal(decimal.RequireFromString("0.01")) {
+		t.Errorf("unexpected series %+v", series)
+	}
+}
@@ -0,0 +1,393 @@
+package polymarketdata
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultArchivePrefix is the file name prefix used when none is configured
+	DefaultArchivePrefix = "archive"
+
+	archiveExt        = ".jsonl.gz"
+	archiveTempExt    = ".tmp"
+	archiveTimeLayout = "20060102T150405.000000000Z"
+)
+
+// ArchiveRecord is a single archived API result together with its request header
+type ArchiveRecord struct {
+	Endpoint  string          `json:"endpoint"`        // API path, e.g. "/trades"
+	Query     url.Values      `json:"query,omitempty"` // Query parameters sent with the request
+	FetchedAt time.Time       `json:"fetchedAt"`       // When the result was fetched
+	Data      json.RawMessage `json:"data"`            // Raw response payload
+}
+
+// Decode unmarshals the record payload into v
+func (r *ArchiveRecord) Decode(v any) error {
+	return json.Unmarshal(r.Data, v)
+}
+
+// ArchiveWriterOptions configures file naming and rotation
+type ArchiveWriterOptions struct {
+	Prefix   string        // Optional: File name prefix. Default "archive"
+	MaxBytes int64         // Optional: Rotate once a file holds about this many compressed bytes. 0 means no limit.
+	MaxAge   time.Duration // Optional: Rotate once a record is fetched this long after the file's first record. 0 means no limit.
+}
+
+// ArchiveWriter appends records to gzip-compressed JSONL files in a directory,
+// rotating by size and time. Files are named after the fetch time of their
+// first record and stay hidden from readers until they are rotated or closed.
+type ArchiveWriter struct {
+	mu   sync.Mutex
+	dir  string
+	opts ArchiveWriterOptions
+
+	file    *os.File
+	counter *countingWriter
+	gz      *gzip.Writer
+	name    string
+	start   time.Time
+	seq     int
+}
+
+// NewArchiveWriter creates a writer for dir, creating the directory if needed
+func NewArchiveWriter(dir string, opts *ArchiveWriterOptions) (*ArchiveWriter, error) {
+	w := &ArchiveWriter{dir: dir}
+	if opts != nil {
+		w.opts = *opts
+	}
+	if w.opts.Prefix == "" {
+		w.opts.Prefix = DefaultArchivePrefix
+	}
+	if strings.ContainsAny(w.opts.Prefix, `/\`) {
+		return nil, fmt.Errorf("archive prefix must not contain path separators")
+	}
+	if w.opts.MaxBytes < 0 || w.opts.MaxAge < 0 {
+		return nil, fmt.Errorf("archive rotation limits must not be negative")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	return w, nil
+}
+
+// Write archives a response payload fetched from endpoint with the given query
+func (w *ArchiveWriter) Write(endpoint string, query url.Values, fetchedAt time.Time, data any) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode archive payload: %w", err)
+	}
+	return w.WriteRecord(&ArchiveRecord{
+		Endpoint:  endpoint,
+		Query:     query,
+		FetchedAt: fetchedAt,
+		Data:      raw,
+	})
+}
+
+// WriteRecord archives a prepared record
+func (w *ArchiveWriter) WriteRecord(rec *ArchiveRecord) error {
+	if rec.Endpoint == "" {
+		return fmt.Errorf("archive record endpoint is required")
+	}
+	if rec.FetchedAt.IsZero() {
+		return fmt.Errorf("archive record fetchedAt is required")
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode archive record: %w", err)
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file != nil && w.shouldRotate(rec.FetchedAt) {
+		if err := w.closeFile(); err != nil {
+			return err
+		}
+	}
+	if w.file == nil {
+		if err := w.openFile(rec.FetchedAt); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.gz.Write(line); err != nil {
+		return fmt.Errorf("failed to write archive record: %w", err)
+	}
+	return nil
+}
+
+// Rotate closes the current file so that the next record starts a new one
+func (w *ArchiveWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closeFile()
+}
+
+// Close flushes and publishes the current file
+func (w *ArchiveWriter) Close() error {
+	return w.Rotate()
+}
+
+func (w *ArchiveWriter) shouldRotate(fetchedAt time.Time) bool {
+	if w.opts.MaxBytes > 0 && w.counter.n >= w.opts.MaxBytes {
+		return true
+	}
+	if w.opts.MaxAge > 0 && fetchedAt.Sub(w.start) >= w.opts.MaxAge {
+		return true
+	}
+	return false
+}
+
+func (w *ArchiveWriter) openFile(start time.Time) error {
+	w.seq++
+	w.name = fmt.Sprintf("%s-%s-%06d%s", w.opts.Prefix, start.UTC().Format(archiveTimeLayout), w.seq, archiveExt)
+	f, err := os.OpenFile(filepath.Join(w.dir, w.name+archiveTempExt), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	w.file = f
+	w.counter = &countingWriter{w: f}
+	w.gz = gzip.NewWriter(w.counter)
+	w.start = start
+	return nil
+}
+
+func (w *ArchiveWriter) closeFile() error {
+	if w.file == nil {
+		return nil
+	}
+	f := w.file
+	w.file = nil
+
+	if err := w.gz.Close(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to flush archive file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close archive file: %w", err)
+	}
+	path := filepath.Join(w.dir, w.name)
+	if err := os.Rename(path+archiveTempExt, path); err != nil {
+		return fmt.Errorf("failed to publish archive file: %w", err)
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// ArchiveFilter selects which records an ArchiveReader returns
+type ArchiveFilter struct {
+	Prefix    string    // Optional: File name prefix. Default "archive"
+	Endpoints []string  // Optional: Only return records for these endpoints
+	Start     time.Time // Optional: Only return records fetched at or after Start
+	End       time.Time // Optional: Only return records fetched before End
+}
+
+// ArchiveReader lazily iterates the records of an archive directory in file order.
+// Files whose time span lies entirely outside the filter range are never opened,
+// assuming records were written in fetch order.
+type ArchiveReader struct {
+	filter    ArchiveFilter
+	endpoints map[string]bool
+	files     []string
+
+	file   *os.File
+	gz     *gzip.Reader
+	br     *bufio.Reader
+	record ArchiveRecord
+	err    error
+}
+
+type archiveFile struct {
+	path  string
+	start time.Time
+}
+
+// OpenArchive prepares a reader over the published files in dir
+func OpenArchive(dir string, filter *ArchiveFilter) (*ArchiveReader, error) {
+	r := &ArchiveReader{}
+	if filter != nil {
+		r.filter = *filter
+	}
+	if r.filter.Prefix == "" {
+		r.filter.Prefix = DefaultArchivePrefix
+	}
+	if len(r.filter.Endpoints) > 0 {
+		r.endpoints = make(map[string]bool, len(r.filter.Endpoints))
+		for _, e := range r.filter.Endpoints {
+			r.endpoints[e] = true
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archive directory: %w", err)
+	}
+
+	var files []archiveFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		start, ok := parseArchiveName(entry.Name(), r.filter.Prefix)
+		if !ok {
+			continue
+		}
+		files = append(files, archiveFile{path: filepath.Join(dir, entry.Name()), start: start})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].path < files[j].path
+	})
+
+	for i, f := range files {
+		if !r.filter.End.IsZero() && !f.start.Before(r.filter.End) {
+			break
+		}
+		if !r.filter.Start.IsZero() && i+1 < len(files) && !files[i+1].start.After(r.filter.Start) {
+			continue
+		}
+		r.files = append(r.files, f.path)
+	}
+
+	return r, nil
+}
+
+// parseArchiveName extracts the start time from a published archive file name
+func parseArchiveName(name, prefix string) (time.Time, bool) {
+	if !strings.HasPrefix(name, prefix+"-") || !strings.HasSuffix(name, archiveExt) {
+		return time.Time{}, false
+	}
+	rest := strings.TrimSuffix(strings.TrimPrefix(name, prefix+"-"), archiveExt)
+	parts := strings.Split(rest, "-")
+	if len(parts) != 2 {
+		return time.Time{}, false
+	}
+	start, err := time.Parse(archiveTimeLayout, parts[0])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+// Next advances to the next matching record, returning false at the end or on error
+func (r *ArchiveReader) Next() bool {
+	for r.err == nil {
+		if r.br == nil {
+			if len(r.files) == 0 {
+				return false
+			}
+			if err := r.openNext(); err != nil {
+				r.err = err
+				return false
+			}
+		}
+
+		line, err := r.br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			r.err = fmt.Errorf("failed to read archive file %s: %w", r.file.Name(), err)
+			return false
+		}
+		if len(line) == 0 {
+			r.closeCurrent()
+			continue
+		}
+		if line[len(line)-1] != '\n' {
+			r.err = fmt.Errorf("truncated archive record in %s", r.file.Name())
+			return false
+		}
+
+		var rec ArchiveRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			r.err = fmt.Errorf("failed to decode archive record in %s: %w", r.file.Name(), err)
+			return false
+		}
+		if r.matches(&rec) {
+			r.record = rec
+			return true
+		}
+	}
+	return false
+}
+
+// Record returns the current record. It is valid until the next call to Next.
+func (r *ArchiveReader) Record() *ArchiveRecord {
+	return &r.record
+}
+
+// Err returns the first error encountered during iteration
+func (r *ArchiveReader) Err() error {
+	return r.err
+}
+
+// Close releases the currently open file
+func (r *ArchiveReader) Close() error {
+	r.files = nil
+	return r.closeCurrent()
+}
+
+func (r *ArchiveReader) matches(rec *ArchiveRecord) bool {
+	if r.endpoints != nil && !r.endpoints[rec.Endpoint] {
+		return false
+	}
+	if !r.filter.Start.IsZero() && rec.FetchedAt.Before(r.filter.Start) {
+		return false
+	}
+	if !r.filter.End.IsZero() && !rec.FetchedAt.Before(r.filter.End) {
+		return false
+	}
+	return true
+}
+
+func (r *ArchiveReader) openNext() error {
+	path := r.files[0]
+	r.files = r.files[1:]
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open archive file: %w", err)
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open archive file %s: %w", path, err)
+	}
+	r.file = f
+	r.gz = gz
+	r.br = bufio.NewReader(gz)
+	return nil
+}
+
+func (r *ArchiveReader) closeCurrent() error {
+	if r.file == nil {
+		return nil
+	}
+	r.gz.Close()
+	err := r.file.Close()
+	r.file, r.gz, r.br = nil, nil, nil
+	return err
+}
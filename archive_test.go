@@ -0,0 +1,142 @@
+package polymarketdata
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func readArchive(t *testing.T, dir string, filter *ArchiveFilter) []ArchiveRecord {
+	t.Helper()
+	r, err := OpenArchive(dir, filter)
+	if err != nil {
+		t.Fatalf("OpenArchive failed: %v", err)
+	}
+	defer r.Close()
+
+	var records []ArchiveRecord
+	for r.Next() {
+		records = append(records, *r.Record())
+	}
+	if err := r.Err(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+	return records
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewArchiveWriter(dir, nil)
+	if err != nil {
+		t.Fatalf("NewArchiveWriter failed: %v", err)
+	}
+
+	fetchedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	trades := []Trade{{ProxyWallet: "0x1", Price: decimal.RequireFromString("0.123456789")}}
+	query := url.Values{"market": {"0xc"}, "limit": {"10"}}
+	if err := w.Write("/trades", query, fetchedAt, trades); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if got := readArchive(t, dir, nil); len(got) != 0 {
+		t.Fatalf("open file should not be visible to readers, got %d records", len(got))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	records := readArchive(t, dir, nil)
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	rec := records[0]
+	if rec.Endpoint != "/trades" || rec.Query.Get("market") != "0xc" || !rec.FetchedAt.Equal(fetchedAt) {
+		t.Errorf("unexpected header: %+v", rec)
+	}
+	var decoded []Trade
+	if err := rec.Decode(&decoded); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !decoded[0].Price.Equal(trades[0].Price) {
+		t.Errorf("price = %s, want %s", decoded[0].Price, trades[0].Price)
+	}
+}
+
+func TestArchiveRotationAndFilters(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewArchiveWriter(dir, &ArchiveWriterOptions{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("NewArchiveWriter failed: %v", err)
+	}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 6; i++ {
+		endpoint := "/trades"
+		if i%2 == 1 {
+			endpoint = "/oi"
+		}
+		if err := w.Write(endpoint, nil, base.Add(time.Duration(i)*30*time.Minute), []int{i}); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 files after hourly rotation, got %d", len(entries))
+	}
+
+	if got := readArchive(t, dir, nil); len(got) != 6 {
+		t.Errorf("expected 6 records, got %d", len(got))
+	}
+	if got := readArchive(t, dir, &ArchiveFilter{Endpoints: []string{"/oi"}}); len(got) != 3 {
+		t.Errorf("expected 3 /oi records, got %d", len(got))
+	}
+
+	got := readArchive(t, dir, &ArchiveFilter{Start: base.Add(time.Hour), End: base.Add(2 * time.Hour)})
+	if len(got) != 2 || !got[0].FetchedAt.Equal(base.Add(time.Hour)) {
+		t.Errorf("time range returned %+v", got)
+	}
+
+	// Corrupt the first file; a range that skips it must not open it
+	first := filepath.Join(dir, entries[0].Name())
+	if err := os.WriteFile(first, []byte("garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := readArchive(t, dir, &ArchiveFilter{Start: base.Add(2 * time.Hour)}); len(got) != 2 {
+		t.Errorf("expected 2 records from the last file, got %d", len(got))
+	}
+}
+
+func TestArchiveSizeRotation(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewArchiveWriter(dir, &ArchiveWriterOptions{Prefix: "trades", MaxBytes: 1})
+	if err != nil {
+		t.Fatalf("NewArchiveWriter failed: %v", err)
+	}
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := w.Write("/trades", nil, now.Add(time.Duration(i)), strings.Repeat("x", 100)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	w.Close()
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 3 {
+		t.Errorf("expected 3 files, got %d", len(entries))
+	}
+	if got := readArchive(t, dir, &ArchiveFilter{Prefix: "trades"}); len(got) != 3 {
+		t.Errorf("expected 3 records, got %d", len(got))
+	}
+	if got := readArchive(t, dir, nil); len(got) != 0 {
+		t.Errorf("default prefix should not match, got %d", len(got))
+	}
+}
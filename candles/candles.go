@@ -0,0 +1,254 @@
+// Package candles aggregates trades into OHLCV candles per outcome token.
+package candles
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+const day = 24 * time.Hour
+
+// Candle is the aggregate of the trades of a single outcome token within an interval
+type Candle struct {
+	Asset        string
+	ConditionId  string
+	OutcomeIndex int
+	Outcome      string
+	Start        time.Time // Inclusive
+	End          time.Time // Exclusive
+	Open         decimal.Decimal
+	High         decimal.Decimal
+	Low          decimal.Decimal
+	Close        decimal.Decimal
+	Volume       decimal.Decimal // Tokens traded
+	BuyVolume    decimal.Decimal // Tokens traded on BUY trades
+	SellVolume   decimal.Decimal // Tokens traded on SELL trades
+	Notional     decimal.Decimal // Cash traded: sum of Size × Price
+	Trades       int
+	BuyTrades    int
+	SellTrades   int
+	Filled       bool // Gap filler without trades: OHLC equal the previous close
+
+	openTs  int64
+	closeTs int64
+}
+
+// Options configures a Builder
+type Options struct {
+	Location *time.Location // Optional: Timezone that intervals are aligned to. Default UTC
+	FillGaps bool           // Optional: Insert empty candles for intervals without trades
+}
+
+// Builder incrementally aggregates trades into candles. Trades may arrive in
+// any order; the open and close are always the earliest and latest trade.
+type Builder struct {
+	interval time.Duration
+	loc      *time.Location
+	fillGaps bool
+
+	series map[string]*series
+	order  []string
+}
+
+type series struct {
+	candles map[int64]*Candle // By start time (Unix seconds)
+}
+
+// NewBuilder creates a builder for the given interval. Intervals that are a
+// whole number of days are aligned to local midnight, so daily candles stay
+// correct across daylight saving changes.
+func NewBuilder(interval time.Duration, opts *Options) (*Builder, error) {
+	if interval < time.Second {
+		return nil, fmt.Errorf("interval must be at least one second")
+	}
+	b := &Builder{
+		interval: interval,
+		loc:      time.UTC,
+		series:   make(map[string]*series),
+	}
+	if opts != nil {
+		if opts.Location != nil {
+			b.loc = opts.Location
+		}
+		b.fillGaps = opts.FillGaps
+	}
+	return b, nil
+}
+
+// Interval returns the builder's candle interval
+func (b *Builder) Interval() time.Duration {
+	return b.interval
+}
+
+// Add aggregates a single trade
+func (b *Builder) Add(t polymarketdata.Trade) {
+	s, ok := b.series[t.Asset]
+	if !ok {
+		s = &series{candles: make(map[int64]*Candle)}
+		b.series[t.Asset] = s
+		b.order = append(b.order, t.Asset)
+	}
+
+	start, end := b.Bucket(time.Unix(t.Timestamp, 0))
+	c, ok := s.candles[start.Unix()]
+	if !ok {
+		c = &Candle{
+			Asset:        t.Asset,
+			ConditionId:  t.ConditionId,
+			OutcomeIndex: t.OutcomeIndex,
+			Outcome:      t.Outcome,
+			Start:        start,
+			End:          end,
+			Open:         t.Price,
+			High:         t.Price,
+			Low:          t.Price,
+			Close:        t.Price,
+			openTs:       t.Timestamp,
+			closeTs:      t.Timestamp,
+		}
+		s.candles[start.Unix()] = c
+	}
+
+	if t.Timestamp < c.openTs {
+		c.Open, c.openTs = t.Price, t.Timestamp
+	}
+	if t.Timestamp >= c.closeTs {
+		c.Close, c.closeTs = t.Price, t.Timestamp
+	}
+	if t.Price.GreaterThan(c.High) {
+		c.High = t.Price
+	}
+	if t.Price.LessThan(c.Low) {
+		c.Low = t.Price
+	}
+
+	c.Volume = c.Volume.Add(t.Size)
+	c.Notional = c.Notional.Add(t.Size.Mul(t.Price))
+	c.Trades++
+	switch t.Side {
+	case polymarketdata.TradeSideBuy:
+		c.BuyVolume = c.BuyVolume.Add(t.Size)
+		c.BuyTrades++
+	case polymarketdata.TradeSideSell:
+		c.SellVolume = c.SellVolume.Add(t.Size)
+		c.SellTrades++
+	}
+}
+
+// AddAll aggregates a slice of trades
+func (b *Builder) AddAll(trades []polymarketdata.Trade) {
+	for _, t := range trades {
+		b.Add(t)
+	}
+}
+
+// AddSeq aggregates every trade produced by an iterator
+func (b *Builder) AddSeq(trades iter.Seq[polymarketdata.Trade]) {
+	for t := range trades {
+		b.Add(t)
+	}
+}
+
+// Assets returns the assets seen so far, in first-seen order
+func (b *Builder) Assets() []string {
+	out := make([]string, len(b.order))
+	copy(out, b.order)
+	return out
+}
+
+// Candles returns the candles of an asset in time order, with gaps filled if configured
+func (b *Builder) Candles(asset string) []Candle {
+	s, ok := b.series[asset]
+	if !ok {
+		return nil
+	}
+
+	starts := make([]int64, 0, len(s.candles))
+	for start := range s.candles {
+		starts = append(starts, start)
+	}
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+
+	out := make([]Candle, 0, len(starts))
+	for _, start := range starts {
+		c := *s.candles[start]
+		if b.fillGaps && len(out) > 0 {
+			prev := out[len(out)-1]
+			for next := prev.End; next.Before(c.Start); {
+				filler := b.filler(prev, next)
+				out = append(out, filler)
+				prev, next = filler, filler.End
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// Latest returns the most recent candle of an asset
+func (b *Builder) Latest(asset string) (Candle, bool) {
+	s, ok := b.series[asset]
+	if !ok {
+		return Candle{}, false
+	}
+	var latest *Candle
+	for _, c := range s.candles {
+		if latest == nil || c.Start.After(latest.Start) {
+			latest = c
+		}
+	}
+	return *latest, true
+}
+
+// Bucket returns the start and end of the interval containing t
+func (b *Builder) Bucket(t time.Time) (start, end time.Time) {
+	local := t.In(b.loc)
+	if b.interval%day == 0 {
+		days := int64(b.interval / day)
+		y, m, d := local.Date()
+		epochDay := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / int64(day/time.Second)
+		startDay := epochDay - mod(epochDay, days)
+		start = time.Unix(startDay*int64(day/time.Second), 0).UTC()
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, b.loc)
+		end = time.Date(start.Year(), start.Month(), start.Day()+int(days), 0, 0, 0, 0, b.loc)
+		return start, end
+	}
+
+	_, offset := local.Zone()
+	size := int64(b.interval / time.Second)
+	shifted := t.Unix() + int64(offset)
+	startUnix := shifted - mod(shifted, size) - int64(offset)
+	start = time.Unix(startUnix, 0).In(b.loc)
+	return start, start.Add(b.interval)
+}
+
+func (b *Builder) filler(prev Candle, at time.Time) Candle {
+	start, end := b.Bucket(at)
+	return Candle{
+		Asset:        prev.Asset,
+		ConditionId:  prev.ConditionId,
+		OutcomeIndex: prev.OutcomeIndex,
+		Outcome:      prev.Outcome,
+		Start:        start,
+		End:          end,
+		Open:         prev.Close,
+		High:         prev.Close,
+		Low:          prev.Close,
+		Close:        prev.Close,
+		Filled:       true,
+	}
+}
+
+// mod returns the non-negative remainder of a divided by b
+func mod(a, b int64) int64 {
+	r := a % b
+	if r < 0 {
+		r += b
+	}
+	return r
+}
@@ -0,0 +1,157 @@
+package candles
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func trade(asset string, ts int64, side polymarketdata.TradeSide, size, price string) polymarketdata.Trade {
+	return polymarketdata.Trade{
+		Side:      side,
+		Asset:     asset,
+		Size:      decimal.RequireFromString(size),
+		Price:     decimal.RequireFromString(price),
+		Timestamp: ts,
+		MarketRef: polymarketdata.MarketRef{Outcome: "Yes"},
+	}
+}
+
+func TestBuilderOHLCV(t *testing.T) {
+	b, err := NewBuilder(time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := int64(1700000000) - 1700000000%3600
+	// Out of order on purpose
+	b.AddAll([]polymarketdata.Trade{
+		trade("a", base+600, polymarketdata.TradeSideSell, "5", "0.55"),
+		trade("a", base+10, polymarketdata.TradeSideBuy, "10", "0.50"),
+		trade("a", base+1200, polymarketdata.TradeSideBuy, "2", "0.70"),
+		trade("a", base+3000, polymarketdata.TradeSideSell, "3", "0.40"),
+		trade("a", base+3600, polymarketdata.TradeSideBuy, "1", "0.45"),
+	})
+
+	got := b.Candles("a")
+	if len(got) != 2 {
+		t.Fatalf("expected 2 candles, got %d", len(got))
+	}
+
+	c := got[0]
+	if c.Start.Unix() != base || c.End.Unix() != base+3600 {
+		t.Errorf("unexpected bounds %v - %v", c.Start, c.End)
+	}
+	checks := []struct {
+		name string
+		got  decimal.Decimal
+		want string
+	}{
+		{"open", c.Open, "0.5"},
+		{"high", c.High, "0.7"},
+		{"low", c.Low, "0.4"},
+		{"close", c.Close, "0.4"},
+		{"volume", c.Volume, "20"},
+		{"buy volume", c.BuyVolume, "12"},
+		{"sell volume", c.SellVolume, "8"},
+		{"notional", c.Notional, "10.35"},
+	}
+	for _, tc := range checks {
+		if !tc.got.Equal(decimal.RequireFromString(tc.want)) {
+			t.Errorf("%s: got %s, want %s", tc.name, tc.got, tc.want)
+		}
+	}
+	if c.Trades != 4 || c.BuyTrades != 2 || c.SellTrades != 2 {
+		t.Errorf("unexpected trade counts %d/%d/%d", c.Trades, c.BuyTrades, c.SellTrades)
+	}
+	if c.Outcome != "Yes" {
+		t.Errorf("expected outcome to be carried over, got %q", c.Outcome)
+	}
+
+	// Incremental update of the latest candle
+	b.Add(trade("a", base+3700, polymarketdata.TradeSideSell, "4", "0.48"))
+	latest, ok := b.Latest("a")
+	if !ok {
+		t.Fatal("expected a latest candle")
+	}
+	if latest.Trades != 2 || !latest.Close.Equal(decimal.RequireFromString("0.48")) {
+		t.Errorf("unexpected latest candle: %+v", latest)
+	}
+}
+
+func TestBuilderFillGaps(t *testing.T) {
+	b, err := NewBuilder(time.Minute, &Options{FillGaps: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Add(trade("a", 60, polymarketdata.TradeSideBuy, "1", "0.3"))
+	b.Add(trade("a", 250, polymarketdata.TradeSideBuy, "1", "0.6"))
+	b.Add(trade("b", 0, polymarketdata.TradeSideBuy, "1", "0.1"))
+
+	got := b.Candles("a")
+	if len(got) != 4 {
+		t.Fatalf("expected 4 candles, got %d", len(got))
+	}
+	for i, c := range got {
+		if c.Start.Unix() != int64(60*(i+1)) {
+			t.Errorf("candle %d starts at %d", i, c.Start.Unix())
+		}
+	}
+	for _, c := range got[1:3] {
+		if !c.Filled || !c.Volume.IsZero() || !c.Close.Equal(decimal.RequireFromString("0.3")) {
+			t.Errorf("unexpected filler candle: %+v", c)
+		}
+	}
+	if got[3].Filled {
+		t.Error("expected the last candle to hold trades")
+	}
+
+	if assets := b.Assets(); !slices.Equal(assets, []string{"a", "b"}) {
+		t.Errorf("unexpected assets %v", assets)
+	}
+	if b.Candles("missing") != nil {
+		t.Error("expected no candles for an unknown asset")
+	}
+}
+
+func TestBuilderTimezone(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("timezone database unavailable")
+	}
+
+	daily, err := NewBuilder(24*time.Hour, &Options{Location: ny})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 2024-03-10 is 23 hours long in New York
+	ts := time.Date(2024, 3, 10, 22, 0, 0, 0, ny).Unix()
+	daily.Add(trade("a", ts, polymarketdata.TradeSideBuy, "1", "0.5"))
+	c, _ := daily.Latest("a")
+	if !c.Start.Equal(time.Date(2024, 3, 10, 0, 0, 0, 0, ny)) || !c.End.Equal(time.Date(2024, 3, 11, 0, 0, 0, 0, ny)) {
+		t.Errorf("unexpected daily bounds %v - %v", c.Start, c.End)
+	}
+	if c.End.Sub(c.Start) != 23*time.Hour {
+		t.Errorf("expected a 23 hour day, got %v", c.End.Sub(c.Start))
+	}
+
+	kolkata := time.FixedZone("IST", 5*3600+1800)
+	hourly, err := NewBuilder(time.Hour, &Options{Location: kolkata})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hourly.Add(trade("a", time.Date(2024, 1, 1, 10, 45, 0, 0, kolkata).Unix(), polymarketdata.TradeSideBuy, "1", "0.5"))
+	c, _ = hourly.Latest("a")
+	if !c.Start.Equal(time.Date(2024, 1, 1, 10, 0, 0, 0, kolkata)) {
+		t.Errorf("expected hour aligned to local time, got %v", c.Start)
+	}
+}
+
+func TestNewBuilderInvalidInterval(t *testing.T) {
+	if _, err := NewBuilder(0, nil); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
@@ -3,20 +3,35 @@ package polymarketdata
 import (
 	"context"
 	"net/http"
+	"sync"
 )
 
 const Endpoint = "https://data-api.polymarket.com"
 
 type Client struct {
-	httpClient *http.Client
+	httpClient    *http.Client
+	directory     *Directory
+	directoryOnce sync.Once
 }
 
 func NewClient(httpClient *http.Client) (*Client, error) {
 	return &Client{
 		httpClient: httpClient,
+		directory:  NewDirectory(),
 	}, nil
 }
 
+// Directory returns the profile and market directory populated from every decoded response.
+// It is created on first use for clients not made by NewClient.
+func (c *Client) Directory() *Directory {
+	c.directoryOnce.Do(func() {
+		if c.directory == nil {
+			c.directory = NewDirectory()
+		}
+	})
+	return c.directory
+}
+
 // doRequest is a helper method to make HTTP requests with context
 func (c *Client) doRequest(ctx context.Context, url string) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -0,0 +1,28 @@
+package polymarketdata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// handlerTransport serves requests from an in-process handler instead of the network
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// newTestClient returns a client whose requests are answered by handler
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	client, err := NewClient(&http.Client{Transport: handlerTransport{handler: handler}})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	return client
+}
@@ -0,0 +1,271 @@
+// Package concentration measures how concentrated the holders of outcome
+// tokens are: Herfindahl-Hirschman index, Gini coefficient, Nakamoto
+// coefficient, top-N share and Shannon entropy.
+//
+// GetHolders only returns the largest holders of each token, up to its
+// Limit and above its MinBalance. Without a known total supply the metrics
+// describe the observed holders only. With Options.TotalSupply, shares are
+// relative to the total supply and the unobserved remainder is assumed to
+// be spread over holders no larger than the smallest observed one.
+package concentration
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// precision is the number of decimal places kept by divisions
+const precision = 16
+
+// DefaultLimit is the number of holders GetHolders returns per token when no Limit is set
+const DefaultLimit = 100
+
+// DefaultTopN is the number of largest holders whose share is reported by default
+const DefaultTopN = 10
+
+// Options configures the metrics
+type Options struct {
+	TopN        int                        // Optional: Number of largest holders in TopShare. Default DefaultTopN
+	Limit       int                        // Optional: The Limit the holders were fetched with. Default DefaultLimit
+	MinBalance  int                        // Optional: The MinBalance the holders were fetched with. Default 1
+	TotalSupply map[string]decimal.Decimal // Optional: Total supply per token, to account for holders missing from the list
+}
+
+// Metrics describes the holder concentration of a single token
+type Metrics struct {
+	Token        string
+	OutcomeIndex int
+	Holders      int             // Observed holders
+	Observed     decimal.Decimal // Amount held by observed holders
+	Total        decimal.Decimal // Total supply if known, otherwise Observed
+	Coverage     decimal.Decimal // Observed / Total
+	Truncated    bool            // The list reached the limit, or the total supply exceeds the observed amount
+
+	HHI               decimal.Decimal // Sum of squared shares, between 0 and 1. Multiply by 10000 for the conventional scale
+	Gini              decimal.Decimal // Among observed holders, between 0 (equal) and 1 (one holder owns all)
+	Nakamoto          int             // Fewest holders that together hold more than half of Total
+	NakamotoEstimated bool            // Nakamoto relies on the assumed unobserved holders
+	TopN              int
+	TopShare          decimal.Decimal // Share of Total held by the TopN largest holders
+	Entropy           decimal.Decimal // Shannon entropy of observed shares, in bits
+	NormalizedEntropy decimal.Decimal // Entropy / log2(Holders), between 0 (one holder) and 1 (equal holdings)
+
+	amounts []decimal.Decimal // Largest first
+}
+
+// Share returns the share of Total held by the n largest holders
+func (m Metrics) Share(n int) decimal.Decimal {
+	if !m.Total.IsPositive() || n <= 0 {
+		return decimal.Zero
+	}
+	var sum decimal.Decimal
+	for i := 0; i < n && i < len(m.amounts); i++ {
+		sum = sum.Add(m.amounts[i])
+	}
+	return sum.DivRound(m.Total, precision)
+}
+
+// Comparison sets the concentration of the outcomes of a condition side by side
+type Comparison struct {
+	ConditionId string
+	Outcomes    []Metrics // By outcome index
+
+	// Outcome 0 (usually YES) minus outcome 1 (usually NO). Zero unless both outcomes have holders.
+	HHIDifference      decimal.Decimal
+	GiniDifference     decimal.Decimal
+	TopShareDifference decimal.Decimal
+
+	MoreConcentrated int // Outcome index with the higher HHI, -1 if equal or unknown
+}
+
+// Outcome returns the metrics of a single outcome
+func (c *Comparison) Outcome(outcomeIndex int) (Metrics, bool) {
+	for _, m := range c.Outcomes {
+		if m.OutcomeIndex == outcomeIndex {
+			return m, true
+		}
+	}
+	return Metrics{}, false
+}
+
+// Measure computes the metrics of a single token's holders
+func Measure(holders polymarketdata.MarketHolders, opts *Options) Metrics {
+	topN := DefaultTopN
+	limit := DefaultLimit
+	minBalance := decimal.NewFromInt(1)
+	var supply *decimal.Decimal
+	if opts != nil {
+		if opts.TopN > 0 {
+			topN = opts.TopN
+		}
+		if opts.Limit > 0 {
+			limit = opts.Limit
+		}
+		if opts.MinBalance > 0 {
+			minBalance = decimal.NewFromInt(int64(opts.MinBalance))
+		}
+		if s, ok := opts.TotalSupply[holders.Token]; ok {
+			supply = &s
+		}
+	}
+
+	m := Metrics{Token: holders.Token, TopN: topN, OutcomeIndex: -1}
+	for _, h := range holders.Holders {
+		if !h.Amount.IsPositive() {
+			continue
+		}
+		m.amounts = append(m.amounts, h.Amount)
+		m.Observed = m.Observed.Add(h.Amount)
+		m.OutcomeIndex = h.OutcomeIndex
+	}
+	sort.Slice(m.amounts, func(i, j int) bool { return m.amounts[i].GreaterThan(m.amounts[j]) })
+	m.Holders = len(m.amounts)
+
+	m.Total = m.Observed
+	if supply != nil && supply.GreaterThan(m.Observed) {
+		m.Total = *supply
+	}
+	m.Truncated = len(holders.Holders) >= limit || m.Total.GreaterThan(m.Observed)
+	if !m.Total.IsPositive() {
+		return m
+	}
+	m.Coverage = m.Observed.DivRound(m.Total, precision)
+	m.TopShare = m.Share(topN)
+
+	for _, a := range m.amounts {
+		s := a.DivRound(m.Total, precision)
+		m.HHI = m.HHI.Add(s.Mul(s))
+	}
+	m.HHI = m.HHI.Round(precision)
+
+	m.Gini = gini(m.amounts, m.Observed)
+	m.Entropy, m.NormalizedEntropy = entropy(m.amounts, m.Observed)
+
+	// Unobserved holders hold at most as much as the smallest observed one,
+	// or less than the minimum balance if the list was not cut off by the limit
+	tail := minBalance
+	if len(holders.Holders) >= limit && len(m.amounts) > 0 {
+		tail = m.amounts[len(m.amounts)-1]
+	}
+	m.Nakamoto, m.NakamotoEstimated = nakamoto(m.amounts, m.Total, tail)
+	return m
+}
+
+// Compute returns the metrics of every token in a GetHolders response
+func Compute(holders []polymarketdata.MarketHolders, opts *Options) []Metrics {
+	out := make([]Metrics, 0, len(holders))
+	for _, h := range holders {
+		out = append(out, Measure(h, opts))
+	}
+	return out
+}
+
+// Compare computes the metrics of the tokens of a single condition and compares
+// its outcomes. The holders must come from a GetHolders call for that condition only.
+func Compare(conditionId string, holders []polymarketdata.MarketHolders, opts *Options) (*Comparison, error) {
+	c := &Comparison{ConditionId: conditionId, MoreConcentrated: -1}
+	seen := make(map[int]string)
+	for _, m := range Compute(holders, opts) {
+		if m.OutcomeIndex < 0 {
+			// No holders, so the outcome is unknown
+			continue
+		}
+		if token, ok := seen[m.OutcomeIndex]; ok {
+			return nil, fmt.Errorf("tokens %s and %s both have outcome index %d", token, m.Token, m.OutcomeIndex)
+		}
+		seen[m.OutcomeIndex] = m.Token
+		c.Outcomes = append(c.Outcomes, m)
+	}
+	sort.Slice(c.Outcomes, func(i, j int) bool {
+		return c.Outcomes[i].OutcomeIndex < c.Outcomes[j].OutcomeIndex
+	})
+
+	first, ok0 := c.Outcome(0)
+	second, ok1 := c.Outcome(1)
+	if ok0 && ok1 {
+		c.HHIDifference = first.HHI.Sub(second.HHI)
+		c.GiniDifference = first.Gini.Sub(second.Gini)
+		c.TopShareDifference = first.TopShare.Sub(second.TopShare)
+		switch c.HHIDifference.Sign() {
+		case 1:
+			c.MoreConcentrated = 0
+		case -1:
+			c.MoreConcentrated = 1
+		}
+	}
+	return c, nil
+}
+
+// Fetch retrieves the holders of a condition and compares its outcomes.
+// The Limit and MinBalance options are passed on to GetHolders.
+func Fetch(ctx context.Context, client *polymarketdata.Client, conditionId string, opts *Options) (*Comparison, error) {
+	params := &polymarketdata.GetHoldersParams{Market: []string{conditionId}}
+	if opts != nil {
+		params.Limit = opts.Limit
+		params.MinBalance = opts.MinBalance
+	}
+	holders, err := client.GetHolders(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch holders: %w", err)
+	}
+	return Compare(conditionId, holders, opts)
+}
+
+// gini computes the Gini coefficient of amounts sorted largest first
+func gini(amounts []decimal.Decimal, sum decimal.Decimal) decimal.Decimal {
+	n := len(amounts)
+	if n < 2 || !sum.IsPositive() {
+		return decimal.Zero
+	}
+	// G = 2 Σ i·x_i / (n Σ x) - (n + 1) / n, with x ascending and i from 1
+	var weighted decimal.Decimal
+	for i, a := range amounts {
+		weighted = weighted.Add(a.Mul(decimal.NewFromInt(int64(n - i))))
+	}
+	nd := decimal.NewFromInt(int64(n))
+	g := weighted.Mul(decimal.NewFromInt(2)).DivRound(nd.Mul(sum), precision).
+		Sub(nd.Add(decimal.NewFromInt(1)).DivRound(nd, precision))
+	if g.IsNegative() {
+		return decimal.Zero
+	}
+	return g
+}
+
+// entropy computes the Shannon entropy of the observed shares and its normalized value
+func entropy(amounts []decimal.Decimal, sum decimal.Decimal) (decimal.Decimal, decimal.Decimal) {
+	if len(amounts) < 2 || !sum.IsPositive() {
+		return decimal.Zero, decimal.Zero
+	}
+	total := sum.InexactFloat64()
+	h := 0.0
+	for _, a := range amounts {
+		p := a.InexactFloat64() / total
+		if p > 0 {
+			h -= p * math.Log2(p)
+		}
+	}
+	return decimal.NewFromFloat(h), decimal.NewFromFloat(h / math.Log2(float64(len(amounts))))
+}
+
+// nakamoto counts the largest holders needed to hold more than half of total.
+// If the observed holders are not enough, unobserved holders of size tail are added.
+func nakamoto(amounts []decimal.Decimal, total, tail decimal.Decimal) (int, bool) {
+	half := total.Div(decimal.NewFromInt(2))
+	var sum decimal.Decimal
+	for i, a := range amounts {
+		sum = sum.Add(a)
+		if sum.GreaterThan(half) {
+			return i + 1, false
+		}
+	}
+	if !tail.IsPositive() {
+		return 0, false
+	}
+	missing := half.Sub(sum).Div(tail).Floor().IntPart() + 1
+	return len(amounts) + int(missing), true
+}
@@ -0,0 +1,138 @@
+package concentration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func holders(token string, outcomeIndex int, amounts ...int64) polymarketdata.MarketHolders {
+	mh := polymarketdata.MarketHolders{Token: token}
+	for _, a := range amounts {
+		mh.Holders = append(mh.Holders, polymarketdata.Holder{Asset: token, Amount: decimal.NewFromInt(a), OutcomeIndex: outcomeIndex})
+	}
+	return mh
+}
+
+func expectDecimal(t *testing.T, name string, got decimal.Decimal, want string) {
+	t.Helper()
+	if !got.Round(4).Equal(decimal.RequireFromString(want)) {
+		t.Errorf("%s: got %s, want %s", name, got, want)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	m := Measure(holders("yes", 0, 30, 50, 20), nil)
+	if m.Holders != 3 || m.OutcomeIndex != 0 || m.Truncated {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+	expectDecimal(t, "hhi", m.HHI, "0.38")
+	expectDecimal(t, "gini", m.Gini, "0.2")
+	expectDecimal(t, "top share", m.TopShare, "1")
+	expectDecimal(t, "share of largest", m.Share(1), "0.5")
+	expectDecimal(t, "entropy", m.Entropy, "1.4855")
+	if m.Nakamoto != 2 || m.NakamotoEstimated {
+		t.Errorf("expected nakamoto 2, got %d", m.Nakamoto)
+	}
+
+	even := Measure(holders("no", 1, 25, 25, 25, 25), nil)
+	expectDecimal(t, "even hhi", even.HHI, "0.25")
+	expectDecimal(t, "even gini", even.Gini, "0")
+	expectDecimal(t, "even entropy", even.Entropy, "2")
+	expectDecimal(t, "even normalized entropy", even.NormalizedEntropy, "1")
+	if even.Nakamoto != 3 {
+		t.Errorf("expected nakamoto 3, got %d", even.Nakamoto)
+	}
+
+	empty := Measure(polymarketdata.MarketHolders{Token: "empty"}, nil)
+	if empty.Holders != 0 || !empty.HHI.IsZero() || empty.Nakamoto != 0 {
+		t.Errorf("unexpected metrics for no holders %+v", empty)
+	}
+}
+
+func TestMeasureTruncated(t *testing.T) {
+	m := Measure(holders("yes", 0, 50, 30, 20), &Options{
+		Limit:       3,
+		TotalSupply: map[string]decimal.Decimal{"yes": decimal.NewFromInt(200)},
+	})
+	if !m.Truncated {
+		t.Error("expected the list to be truncated")
+	}
+	expectDecimal(t, "coverage", m.Coverage, "0.5")
+	expectDecimal(t, "top share", m.TopShare, "0.5")
+	expectDecimal(t, "hhi", m.HHI, "0.095")
+	// Half of 200 is reached with one more holder of at most 20
+	if m.Nakamoto != 4 || !m.NakamotoEstimated {
+		t.Errorf("expected estimated nakamoto 4, got %d (%v)", m.Nakamoto, m.NakamotoEstimated)
+	}
+
+	limited := Measure(holders("yes", 0, 50, 30, 20), &Options{Limit: 3})
+	if !limited.Truncated {
+		t.Error("expected a full page to count as truncated")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	c, err := Compare("0xc", []polymarketdata.MarketHolders{
+		holders("no", 1, 25, 25, 25, 25),
+		holders("yes", 0, 50, 30, 20),
+	}, &Options{TopN: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Outcomes) != 2 || c.Outcomes[0].Token != "yes" {
+		t.Fatalf("unexpected outcomes %+v", c.Outcomes)
+	}
+	expectDecimal(t, "hhi difference", c.HHIDifference, "0.13")
+	expectDecimal(t, "gini difference", c.GiniDifference, "0.2")
+	expectDecimal(t, "top share difference", c.TopShareDifference, "0.25")
+	if c.MoreConcentrated != 0 {
+		t.Errorf("expected outcome 0 to be more concentrated, got %d", c.MoreConcentrated)
+	}
+
+	if _, err := Compare("0xc", []polymarketdata.MarketHolders{holders("a", 0, 1), holders("b", 0, 1)}, nil); err == nil {
+		t.Error("expected an error for duplicate outcome indexes")
+	}
+}
+
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+func TestFetch(t *testing.T) {
+	client, err := polymarketdata.NewClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("market") != "0xc" || q.Get("limit") != "2" || q.Get("minBalance") != "5" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`[
+			{"token":"yes","holders":[{"asset":"yes","amount":90,"outcomeIndex":0},{"asset":"yes","amount":10,"outcomeIndex":0}]},
+			{"token":"no","holders":[{"asset":"no","amount":50,"outcomeIndex":1},{"asset":"no","amount":50,"outcomeIndex":1}]}
+		]`))
+	})}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Fetch(context.Background(), client, "0xc", &Options{Limit: 2, MinBalance: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	yes, ok := c.Outcome(0)
+	if !ok || !yes.Truncated || yes.Nakamoto != 1 {
+		t.Errorf("unexpected yes metrics %+v", yes)
+	}
+	if c.MoreConcentrated != 0 {
+		t.Errorf("expected outcome 0 to be more concentrated, got %d", c.MoreConcentrated)
+	}
+}
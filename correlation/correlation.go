@@ -0,0 +1,260 @@
+// Package correlation measures how markets move together, from the pairwise
+// correlation of their price changes over aligned intervals.
+package correlation
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/ivanzzeth/polymarket-go-data-client/candles"
+	"github.com/shopspring/decimal"
+)
+
+// precision is the number of decimal places kept by divisions and square roots
+const precision = 16
+
+// DefaultMinOverlap is the minimum number of shared intervals for a correlation
+const DefaultMinOverlap = 10
+
+var one = decimal.NewFromInt(1)
+
+// Options configures an Analyzer
+type Options struct {
+	MinOverlap int            // Optional: Minimum number of shared intervals for a correlation. Default DefaultMinOverlap
+	Location   *time.Location // Optional: Timezone that intervals are aligned to. Default UTC
+}
+
+// Pair is the correlation of two markets
+type Pair struct {
+	A           string
+	B           string
+	Correlation decimal.Decimal // Pearson correlation of price changes, between -1 and 1
+	Overlap     int             // Intervals in which both markets traded; gap-filled intervals do not count
+	Valid       bool            // Overlap reached the minimum and neither series was flat
+}
+
+// Matrix holds the pairwise correlations of a set of markets
+type Matrix struct {
+	Interval   time.Duration
+	Conditions []string
+	Values     [][]decimal.NullDecimal // Values[i][j] correlates Conditions[i] and Conditions[j]; invalid pairs are null
+	Pairs      []Pair                  // Every pair once, A before B in Conditions order
+}
+
+// Pair returns the correlation of two markets in either order
+func (m *Matrix) Pair(a, b string) (Pair, bool) {
+	for _, p := range m.Pairs {
+		if (p.A == a && p.B == b) || (p.A == b && p.B == a) {
+			return p, true
+		}
+	}
+	return Pair{}, false
+}
+
+// TopPairs returns up to n valid pairs with the strongest correlation by
+// absolute value, so strongly inverse markets rank alongside aligned ones.
+// Ties are broken by condition ID.
+func (m *Matrix) TopPairs(n int) []Pair {
+	var out []Pair
+	for _, p := range m.Pairs {
+		if p.Valid {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if c := out[i].Correlation.Abs().Cmp(out[j].Correlation.Abs()); c != 0 {
+			return c > 0
+		}
+		if out[i].A != out[j].A {
+			return out[i].A < out[j].A
+		}
+		return out[i].B < out[j].B
+	})
+	if n >= 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// Analyzer incrementally aggregates trades into YES-price candles per market
+// and correlates them on demand. Trades of the NO token (outcome index 1) at
+// price p count as a YES price of 1 - p, so both tokens feed one series.
+type Analyzer struct {
+	builder    *candles.Builder
+	minOverlap int
+}
+
+// NewAnalyzer creates an analyzer that aligns prices to the given interval
+func NewAnalyzer(interval time.Duration, opts *Options) (*Analyzer, error) {
+	a := &Analyzer{minOverlap: DefaultMinOverlap}
+	builderOpts := &candles.Options{FillGaps: true}
+	if opts != nil {
+		if opts.MinOverlap > 0 {
+			a.minOverlap = opts.MinOverlap
+		}
+		builderOpts.Location = opts.Location
+	}
+	builder, err := candles.NewBuilder(interval, builderOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create candle builder: %w", err)
+	}
+	a.builder = builder
+	return a, nil
+}
+
+// Add aggregates a single trade. Trades of outcomes beyond the second are ignored.
+func (a *Analyzer) Add(t polymarketdata.Trade) {
+	switch t.OutcomeIndex {
+	case 0:
+	case 1:
+		t.Price = one.Sub(t.Price)
+		t.OutcomeIndex = 0
+	default:
+		return
+	}
+	t.Asset = t.ConditionId
+	a.builder.Add(t)
+}
+
+// AddAll aggregates a slice of trades
+func (a *Analyzer) AddAll(trades []polymarketdata.Trade) {
+	for _, t := range trades {
+		a.Add(t)
+	}
+}
+
+// AddSeq aggregates every trade produced by an iterator
+func (a *Analyzer) AddSeq(trades iter.Seq[polymarketdata.Trade]) {
+	for t := range trades {
+		a.Add(t)
+	}
+}
+
+// Conditions returns the markets seen so far, in first-seen order
+func (a *Analyzer) Conditions() []string {
+	return a.builder.Assets()
+}
+
+// Changes returns a market's close-to-close price changes by interval start.
+// Intervals without trades carry the previous close forward. Price changes are
+// used instead of percentage returns, which explode for prices near zero.
+func (a *Analyzer) Changes(conditionId string) map[time.Time]decimal.Decimal {
+	out, _ := a.changes(conditionId)
+	return out
+}
+
+// changes returns a market's price changes and the intervals in which it traded
+func (a *Analyzer) changes(conditionId string) (map[time.Time]decimal.Decimal, map[time.Time]bool) {
+	cs := a.builder.Candles(conditionId)
+	out := make(map[time.Time]decimal.Decimal, len(cs))
+	traded := make(map[time.Time]bool, len(cs))
+	for i := 1; i < len(cs); i++ {
+		out[cs[i].Start] = cs[i].Close.Sub(cs[i-1].Close)
+		if !cs[i].Filled {
+			traded[cs[i].Start] = true
+		}
+	}
+	return out, traded
+}
+
+// Matrix correlates every pair of the given markets, or of every market seen if none are given
+func (a *Analyzer) Matrix(conditions ...string) *Matrix {
+	if len(conditions) == 0 {
+		conditions = a.Conditions()
+	}
+	m := &Matrix{
+		Interval:   a.builder.Interval(),
+		Conditions: conditions,
+		Values:     make([][]decimal.NullDecimal, len(conditions)),
+	}
+	changes := make([]map[time.Time]decimal.Decimal, len(conditions))
+	traded := make([]map[time.Time]bool, len(conditions))
+	for i, c := range conditions {
+		changes[i], traded[i] = a.changes(c)
+		m.Values[i] = make([]decimal.NullDecimal, len(conditions))
+	}
+
+	for i := range conditions {
+		for j := i + 1; j < len(conditions); j++ {
+			p := a.pair(conditions[i], conditions[j], changes[i], changes[j], traded[i], traded[j])
+			m.Pairs = append(m.Pairs, p)
+			if p.Valid {
+				m.Values[i][j] = decimal.NewNullDecimal(p.Correlation)
+				m.Values[j][i] = decimal.NewNullDecimal(p.Correlation)
+			}
+		}
+		if len(traded[i]) >= a.minOverlap {
+			m.Values[i][i] = decimal.NewNullDecimal(one)
+		}
+	}
+	return m
+}
+
+// pair correlates the changes of the intervals in which both markets traded.
+// Gap-filled intervals are left out, as their zero changes would pull sparse
+// markets towards no correlation while counting as overlap.
+func (a *Analyzer) pair(condA, condB string, changesA, changesB map[time.Time]decimal.Decimal, tradedA, tradedB map[time.Time]bool) Pair {
+	var xs, ys []decimal.Decimal
+	for start, x := range changesA {
+		if !tradedA[start] || !tradedB[start] {
+			continue
+		}
+		if y, ok := changesB[start]; ok {
+			xs = append(xs, x)
+			ys = append(ys, y)
+		}
+	}
+	p := Pair{A: condA, B: condB, Overlap: len(xs)}
+	if p.Overlap < a.minOverlap {
+		return p
+	}
+	p.Correlation, p.Valid = Pearson(xs, ys)
+	return p
+}
+
+// Compute correlates the markets traded in trades at the given interval
+func Compute(trades []polymarketdata.Trade, interval time.Duration, opts *Options) (*Matrix, error) {
+	a, err := NewAnalyzer(interval, opts)
+	if err != nil {
+		return nil, err
+	}
+	a.AddAll(trades)
+	return a.Matrix(), nil
+}
+
+// Pearson returns the Pearson correlation of two equally long series, or
+// false if they are shorter than two values or either has no variance
+func Pearson(xs, ys []decimal.Decimal) (decimal.Decimal, bool) {
+	if len(xs) != len(ys) || len(xs) < 2 {
+		return decimal.Zero, false
+	}
+	n := decimal.NewFromInt(int64(len(xs)))
+	meanX := decimal.Sum(xs[0], xs[1:]...).DivRound(n, precision)
+	meanY := decimal.Sum(ys[0], ys[1:]...).DivRound(n, precision)
+
+	var cov, varX, varY decimal.Decimal
+	for i := range xs {
+		dx, dy := xs[i].Sub(meanX), ys[i].Sub(meanY)
+		cov = cov.Add(dx.Mul(dy))
+		varX = varX.Add(dx.Mul(dx))
+		varY = varY.Add(dy.Mul(dy))
+	}
+	if !varX.IsPositive() || !varY.IsPositive() {
+		return decimal.Zero, false
+	}
+	denom, err := varX.Mul(varY).PowWithPrecision(decimal.NewFromFloat(0.5), precision)
+	if err != nil || !denom.IsPositive() {
+		return decimal.Zero, false
+	}
+	r := cov.DivRound(denom, precision)
+	// Rounding may push a perfect correlation just past ±1
+	if r.GreaterThan(one) {
+		r = one
+	} else if r.LessThan(one.Neg()) {
+		r = one.Neg()
+	}
+	return r, true
+}
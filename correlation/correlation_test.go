@@ -0,0 +1,120 @@
+package correlation
+
+import (
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func trade(condition string, outcomeIndex int, hour int64, price float64) polymarketdata.Trade {
+	return polymarketdata.Trade{
+		ConditionId:  condition,
+		Asset:        condition + "-token",
+		OutcomeIndex: outcomeIndex,
+		Timestamp:    hour * 3600,
+		Size:         decimal.NewFromInt(1),
+		Price:        decimal.NewFromFloat(price),
+	}
+}
+
+func TestPearson(t *testing.T) {
+	d := func(vs ...int64) []decimal.Decimal {
+		out := make([]decimal.Decimal, len(vs))
+		for i, v := range vs {
+			out[i] = decimal.NewFromInt(v)
+		}
+		return out
+	}
+
+	if r, ok := Pearson(d(1, 2, 3, 4), d(2, 4, 6, 8)); !ok || !r.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("expected 1, got %s %v", r, ok)
+	}
+	if r, ok := Pearson(d(1, 2, 3), d(3, 2, 1)); !ok || !r.Equal(decimal.NewFromInt(-1)) {
+		t.Errorf("expected -1, got %s %v", r, ok)
+	}
+	if r, ok := Pearson(d(1, 2, 3, 4), d(1, 3, 2, 4)); !ok || !r.Equal(decimal.RequireFromString("0.8")) {
+		t.Errorf("expected 0.8, got %s %v", r, ok)
+	}
+	if _, ok := Pearson(d(1, 2, 3), d(5, 5, 5)); ok {
+		t.Error("expected no correlation for a flat series")
+	}
+}
+
+func TestMatrix(t *testing.T) {
+	prices := []float64{0.50, 0.55, 0.52, 0.60, 0.58, 0.65}
+	var trades []polymarketdata.Trade
+	for h, p := range prices {
+		trades = append(trades,
+			trade("0xa", 0, int64(h), p),
+			// The same moves seen through the NO token
+			trade("0xb", 1, int64(h), 1-p),
+			// Moves in the opposite direction
+			trade("0xc", 0, int64(h), 1-p),
+		)
+	}
+	// 0xd trades rarely; its gaps carry the last price forward
+	trades = append(trades, trade("0xd", 0, 0, 0.3), trade("0xd", 0, 5, 0.4))
+
+	m, err := Compute(trades, time.Hour, &Options{MinOverlap: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Conditions) != 4 || len(m.Pairs) != 6 {
+		t.Fatalf("unexpected matrix %+v", m)
+	}
+
+	ab, _ := m.Pair("0xb", "0xa")
+	if !ab.Valid || ab.Overlap != 5 || !ab.Correlation.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("expected 0xa and 0xb to be perfectly correlated, got %+v", ab)
+	}
+	if v := m.Values[0][2]; !v.Valid || !v.Decimal.Equal(decimal.NewFromInt(-1)) {
+		t.Errorf("expected 0xa and 0xc to be inversely correlated, got %+v", v)
+	}
+	if !m.Values[0][0].Valid || !m.Values[0][0].Decimal.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("expected a unit diagonal, got %+v", m.Values[0][0])
+	}
+
+	// Gap-filled intervals of 0xd do not count as overlap
+	if ad, _ := m.Pair("0xa", "0xd"); ad.Valid || ad.Overlap != 1 {
+		t.Errorf("expected 0xa and 0xd to overlap in one interval only, got %+v", ad)
+	}
+	if m.Values[3][3].Valid {
+		t.Errorf("expected no diagonal for a market with too few traded intervals")
+	}
+
+	top := m.TopPairs(2)
+	if len(top) != 2 || top[0].A != "0xa" || top[0].B != "0xb" || top[1].B != "0xc" {
+		t.Errorf("unexpected top pairs %+v", top)
+	}
+
+	// Too little overlap leaves the pair invalid
+	m, err = Compute(trades, time.Hour, &Options{MinOverlap: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.TopPairs(-1)) != 0 || m.Values[0][1].Valid {
+		t.Errorf("expected no valid pairs below the minimum overlap")
+	}
+}
+
+func TestIncremental(t *testing.T) {
+	a, err := NewAnalyzer(time.Hour, &Options{MinOverlap: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.AddAll([]polymarketdata.Trade{trade("0xa", 0, 0, 0.5), trade("0xb", 0, 0, 0.5), trade("0xa", 0, 1, 0.6), trade("0xb", 0, 1, 0.6)})
+	if p, _ := a.Matrix().Pair("0xa", "0xb"); p.Valid {
+		t.Fatalf("expected a single interval to be too little, got %+v", p)
+	}
+
+	a.AddAll([]polymarketdata.Trade{trade("0xa", 0, 2, 0.4), trade("0xb", 0, 2, 0.45)})
+	if p, _ := a.Matrix("0xa", "0xb").Pair("0xa", "0xb"); !p.Valid || !p.Correlation.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("expected a valid correlation after more trades, got %+v", p)
+	}
+
+	if _, err := NewAnalyzer(0, nil); err == nil {
+		t.Error("expected an error for a zero interval")
+	}
+}
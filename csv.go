@@ -0,0 +1,496 @@
+package polymarketdata
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+)
+
+// csvColumn maps a single CSV column to a field of T
+type csvColumn[T any] struct {
+	name string
+	get  func(*T) string
+	set  func(*T, string) error
+}
+
+// CSVSchema describes the columns available when exporting T to CSV.
+// Column names match the JSON field names of the API.
+type CSVSchema[T any] struct {
+	columns []csvColumn[T]
+	index   map[string]int
+}
+
+func newCSVSchema[T any](columns ...csvColumn[T]) *CSVSchema[T] {
+	index := make(map[string]int, len(columns))
+	for i, col := range columns {
+		index[col.name] = i
+	}
+	return &CSVSchema[T]{columns: columns, index: index}
+}
+
+// Columns returns all column names in their default order
+func (s *CSVSchema[T]) Columns() []string {
+	names := make([]string, len(s.columns))
+	for i, col := range s.columns {
+		names[i] = col.name
+	}
+	return names
+}
+
+// lookup resolves column names to columns, keeping the requested order
+func (s *CSVSchema[T]) lookup(names []string) ([]csvColumn[T], error) {
+	cols := make([]csvColumn[T], len(names))
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		idx, ok := s.index[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown csv column %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate csv column %q", name)
+		}
+		seen[name] = true
+		cols[i] = s.columns[idx]
+	}
+	return cols, nil
+}
+
+func stringColumn[T any](name string, field func(*T) *string) csvColumn[T] {
+	return csvColumn[T]{
+		name: name,
+		get:  func(v *T) string { return *field(v) },
+		set: func(v *T, s string) error {
+			*field(v) = s
+			return nil
+		},
+	}
+}
+
+func decimalColumn[T any](name string, field func(*T) *decimal.Decimal) csvColumn[T] {
+	return csvColumn[T]{
+		name: name,
+		get:  func(v *T) string { return field(v).String() },
+		set: func(v *T, s string) error {
+			if s == "" {
+				*field(v) = decimal.Zero
+				return nil
+			}
+			d, err := decimal.NewFromString(s)
+			if err != nil {
+				return err
+			}
+			*field(v) = d
+			return nil
+		},
+	}
+}
+
+func intColumn[T any](name string, field func(*T) *int) csvColumn[T] {
+	return csvColumn[T]{
+		name: name,
+		get:  func(v *T) string { return strconv.Itoa(*field(v)) },
+		set: func(v *T, s string) error {
+			if s == "" {
+				*field(v) = 0
+				return nil
+			}
+			n, err := strconv.Atoi(s)
+			if err != nil {
+				return err
+			}
+			*field(v) = n
+			return nil
+		},
+	}
+}
+
+func int64Column[T any](name string, field func(*T) *int64) csvColumn[T] {
+	return csvColumn[T]{
+		name: name,
+		get:  func(v *T) string { return strconv.FormatInt(*field(v), 10) },
+		set: func(v *T, s string) error {
+			if s == "" {
+				*field(v) = 0
+				return nil
+			}
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return err
+			}
+			*field(v) = n
+			return nil
+		},
+	}
+}
+
+func boolColumn[T any](name string, field func(*T) *bool) csvColumn[T] {
+	return csvColumn[T]{
+		name: name,
+		get:  func(v *T) string { return strconv.FormatBool(*field(v)) },
+		set: func(v *T, s string) error {
+			if s == "" {
+				*field(v) = false
+				return nil
+			}
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return err
+			}
+			*field(v) = b
+			return nil
+		},
+	}
+}
+
+// marketRefColumns returns the MarketRef columns for any type embedding it
+func marketRefColumns[T any](ref func(*T) *MarketRef) []csvColumn[T] {
+	return []csvColumn[T]{
+		stringColumn("title", func(v *T) *string { return &ref(v).Title }),
+		stringColumn("slug", func(v *T) *string { return &ref(v).Slug }),
+		stringColumn("icon", func(v *T) *string { return &ref(v).Icon }),
+		stringColumn("eventSlug", func(v *T) *string { return &ref(v).EventSlug }),
+		stringColumn("outcome", func(v *T) *string { return &ref(v).Outcome }),
+	}
+}
+
+// userProfileColumns returns the UserProfile columns for any type embedding it
+func userProfileColumns[T any](profile func(*T) *UserProfile) []csvColumn[T] {
+	return []csvColumn[T]{
+		stringColumn("name", func(v *T) *string { return &profile(v).Name }),
+		stringColumn("pseudonym", func(v *T) *string { return &profile(v).Pseudonym }),
+		stringColumn("bio", func(v *T) *string { return &profile(v).Bio }),
+		stringColumn("profileImage", func(v *T) *string { return &profile(v).ProfileImage }),
+		stringColumn("profileImageOptimized", func(v *T) *string { return &profile(v).ProfileImageOptimized }),
+	}
+}
+
+func concatColumns[T any](groups ...[]csvColumn[T]) []csvColumn[T] {
+	var all []csvColumn[T]
+	for _, g := range groups {
+		all = append(all, g...)
+	}
+	return all
+}
+
+// HolderRow is a Holder flattened together with the token of its MarketHolders
+type HolderRow struct {
+	Token string
+	Holder
+}
+
+// FlattenHolders turns a GetHolders response into one row per holder
+func FlattenHolders(holders []MarketHolders) []HolderRow {
+	var rows []HolderRow
+	for _, mh := range holders {
+		for _, h := range mh.Holders {
+			rows = append(rows, HolderRow{Token: mh.Token, Holder: h})
+		}
+	}
+	return rows
+}
+
+// GroupHolders rebuilds a GetHolders response from flattened rows, keeping token order
+func GroupHolders(rows []HolderRow) []MarketHolders {
+	var holders []MarketHolders
+	index := make(map[string]int)
+	for _, row := range rows {
+		i, ok := index[row.Token]
+		if !ok {
+			i = len(holders)
+			index[row.Token] = i
+			holders = append(holders, MarketHolders{Token: row.Token})
+		}
+		holders[i].Holders = append(holders[i].Holders, row.Holder)
+	}
+	return holders
+}
+
+// LiveVolumeRow is a LiveVolumeMarket flattened together with its parent total.
+// Index is the position of the parent LiveVolume in the response. A LiveVolume
+// without markets is written as a single row with an empty Market.
+type LiveVolumeRow struct {
+	Index  int
+	Total  decimal.Decimal
+	Market string
+	Value  decimal.Decimal
+}
+
+// FlattenLiveVolume turns a GetLiveVolume response into one row per market
+func FlattenLiveVolume(volumes []LiveVolume) []LiveVolumeRow {
+	var rows []LiveVolumeRow
+	for i, v := range volumes {
+		if len(v.Markets) == 0 {
+			rows = append(rows, LiveVolumeRow{Index: i, Total: v.Total})
+			continue
+		}
+		for _, m := range v.Markets {
+			rows = append(rows, LiveVolumeRow{Index: i, Total: v.Total, Market: m.Market, Value: m.Value})
+		}
+	}
+	return rows
+}
+
+// GroupLiveVolume rebuilds a GetLiveVolume response from flattened rows
+func GroupLiveVolume(rows []LiveVolumeRow) []LiveVolume {
+	var volumes []LiveVolume
+	index := make(map[int]int)
+	for _, row := range rows {
+		i, ok := index[row.Index]
+		if !ok {
+			i = len(volumes)
+			index[row.Index] = i
+			volumes = append(volumes, LiveVolume{Total: row.Total})
+		}
+		if row.Market != "" {
+			volumes[i].Markets = append(volumes[i].Markets, LiveVolumeMarket{Market: row.Market, Value: row.Value})
+		}
+	}
+	return volumes
+}
+
+// PositionCSV is the CSV schema for Position
+var PositionCSV = newCSVSchema(concatColumns(
+	[]csvColumn[Position]{
+		stringColumn("proxyWallet", func(v *Position) *string { return &v.ProxyWallet }),
+		stringColumn("asset", func(v *Position) *string { return &v.Asset }),
+		stringColumn("conditionId", func(v *Position) *string { return &v.ConditionId }),
+		decimalColumn("size", func(v *Position) *decimal.Decimal { return &v.Size }),
+		decimalColumn("avgPrice", func(v *Position) *decimal.Decimal { return &v.AvgPrice }),
+		decimalColumn("initialValue", func(v *Position) *decimal.Decimal { return &v.InitialValue }),
+		decimalColumn("currentValue", func(v *Position) *decimal.Decimal { return &v.CurrentValue }),
+		decimalColumn("cashPnl", func(v *Position) *decimal.Decimal { return &v.CashPnl }),
+		decimalColumn("percentPnl", func(v *Position) *decimal.Decimal { return &v.PercentPnl }),
+		decimalColumn("totalBought", func(v *Position) *decimal.Decimal { return &v.TotalBought }),
+		decimalColumn("realizedPnl", func(v *Position) *decimal.Decimal { return &v.RealizedPnl }),
+		decimalColumn("percentRealizedPnl", func(v *Position) *decimal.Decimal { return &v.PercentRealizedPnl }),
+		decimalColumn("curPrice", func(v *Position) *decimal.Decimal { return &v.CurPrice }),
+		boolColumn("redeemable", func(v *Position) *bool { return &v.Redeemable }),
+		boolColumn("mergeable", func(v *Position) *bool { return &v.Mergeable }),
+	},
+	marketRefColumns(func(v *Position) *MarketRef { return &v.MarketRef }),
+	[]csvColumn[Position]{
+		intColumn("outcomeIndex", func(v *Position) *int { return &v.OutcomeIndex }),
+		stringColumn("oppositeOutcome", func(v *Position) *string { return &v.OppositeOutcome }),
+		stringColumn("oppositeAsset", func(v *Position) *string { return &v.OppositeAsset }),
+		stringColumn("endDate", func(v *Position) *string { return &v.EndDate }),
+		boolColumn("negativeRisk", func(v *Position) *bool { return &v.NegativeRisk }),
+	},
+)...)
+
+// ClosedPositionCSV is the CSV schema for ClosedPosition
+var ClosedPositionCSV = newCSVSchema(concatColumns(
+	[]csvColumn[ClosedPosition]{
+		stringColumn("proxyWallet", func(v *ClosedPosition) *string { return &v.ProxyWallet }),
+		stringColumn("asset", func(v *ClosedPosition) *string { return &v.Asset }),
+		stringColumn("conditionId", func(v *ClosedPosition) *string { return &v.ConditionId }),
+		decimalColumn("avgPrice", func(v *ClosedPosition) *decimal.Decimal { return &v.AvgPrice }),
+		decimalColumn("totalBought", func(v *ClosedPosition) *decimal.Decimal { return &v.TotalBought }),
+		decimalColumn("realizedPnl", func(v *ClosedPosition) *decimal.Decimal { return &v.RealizedPnl }),
+		decimalColumn("curPrice", func(v *ClosedPosition) *decimal.Decimal { return &v.CurPrice }),
+	},
+	marketRefColumns(func(v *ClosedPosition) *MarketRef { return &v.MarketRef }),
+	[]csvColumn[ClosedPosition]{
+		intColumn("outcomeIndex", func(v *ClosedPosition) *int { return &v.OutcomeIndex }),
+		stringColumn("oppositeOutcome", func(v *ClosedPosition) *string { return &v.OppositeOutcome }),
+		stringColumn("oppositeAsset", func(v *ClosedPosition) *string { return &v.OppositeAsset }),
+		stringColumn("endDate", func(v *ClosedPosition) *string { return &v.EndDate }),
+	},
+)...)
+
+// TradeCSV is the CSV schema for Trade
+var TradeCSV = newCSVSchema(concatColumns(
+	[]csvColumn[Trade]{
+		stringColumn("proxyWallet", func(v *Trade) *string { return &v.ProxyWallet }),
+		stringColumn("side", func(v *Trade) *string { return (*string)(&v.Side) }),
+		stringColumn("asset", func(v *Trade) *string { return &v.Asset }),
+		stringColumn("conditionId", func(v *Trade) *string { return &v.ConditionId }),
+		decimalColumn("size", func(v *Trade) *decimal.Decimal { return &v.Size }),
+		decimalColumn("price", func(v *Trade) *decimal.Decimal { return &v.Price }),
+		int64Column("timestamp", func(v *Trade) *int64 { return &v.Timestamp }),
+	},
+	marketRefColumns(func(v *Trade) *MarketRef { return &v.MarketRef }),
+	[]csvColumn[Trade]{
+		intColumn("outcomeIndex", func(v *Trade) *int { return &v.OutcomeIndex }),
+	},
+	userProfileColumns(func(v *Trade) *UserProfile { return &v.UserProfile }),
+	[]csvColumn[Trade]{
+		stringColumn("transactionHash", func(v *Trade) *string { return &v.TransactionHash }),
+	},
+)...)
+
+// ActivityCSV is the CSV schema for Activity
+var ActivityCSV = newCSVSchema(concatColumns(
+	[]csvColumn[Activity]{
+		stringColumn("proxyWallet", func(v *Activity) *string { return &v.ProxyWallet }),
+		int64Column("timestamp", func(v *Activity) *int64 { return &v.Timestamp }),
+		stringColumn("conditionId", func(v *Activity) *string { return &v.ConditionId }),
+		stringColumn("type", func(v *Activity) *string { return (*string)(&v.Type) }),
+		decimalColumn("size", func(v *Activity) *decimal.Decimal { return &v.Size }),
+		decimalColumn("usdcSize", func(v *Activity) *decimal.Decimal { return &v.UsdcSize }),
+		stringColumn("transactionHash", func(v *Activity) *string { return &v.TransactionHash }),
+		decimalColumn("price", func(v *Activity) *decimal.Decimal { return &v.Price }),
+		stringColumn("asset", func(v *Activity) *string { return &v.Asset }),
+		stringColumn("side", func(v *Activity) *string { return (*string)(&v.Side) }),
+		intColumn("outcomeIndex", func(v *Activity) *int { return &v.OutcomeIndex }),
+	},
+	marketRefColumns(func(v *Activity) *MarketRef { return &v.MarketRef }),
+	userProfileColumns(func(v *Activity) *UserProfile { return &v.UserProfile }),
+)...)
+
+// HolderCSV is the CSV schema for HolderRow
+var HolderCSV = newCSVSchema(concatColumns(
+	[]csvColumn[HolderRow]{
+		stringColumn("token", func(v *HolderRow) *string { return &v.Token }),
+		stringColumn("proxyWallet", func(v *HolderRow) *string { return &v.ProxyWallet }),
+		stringColumn("asset", func(v *HolderRow) *string { return &v.Asset }),
+		decimalColumn("amount", func(v *HolderRow) *decimal.Decimal { return &v.Amount }),
+		boolColumn("displayUsernamePublic", func(v *HolderRow) *bool { return &v.DisplayUsernamePublic }),
+		intColumn("outcomeIndex", func(v *HolderRow) *int { return &v.OutcomeIndex }),
+	},
+	userProfileColumns(func(v *HolderRow) *UserProfile { return &v.UserProfile }),
+)...)
+
+// UserValueCSV is the CSV schema for UserValue
+var UserValueCSV = newCSVSchema(
+	stringColumn("user", func(v *UserValue) *string { return &v.User }),
+	decimalColumn("value", func(v *UserValue) *decimal.Decimal { return &v.Value }),
+)
+
+// OpenInterestCSV is the CSV schema for OpenInterest
+var OpenInterestCSV = newCSVSchema(
+	stringColumn("market", func(v *OpenInterest) *string { return &v.Market }),
+	decimalColumn("value", func(v *OpenInterest) *decimal.Decimal { return &v.Value }),
+)
+
+// LiveVolumeCSV is the CSV schema for LiveVolumeRow
+var LiveVolumeCSV = newCSVSchema(
+	intColumn("index", func(v *LiveVolumeRow) *int { return &v.Index }),
+	decimalColumn("total", func(v *LiveVolumeRow) *decimal.Decimal { return &v.Total }),
+	stringColumn("market", func(v *LiveVolumeRow) *string { return &v.Market }),
+	decimalColumn("value", func(v *LiveVolumeRow) *decimal.Decimal { return &v.Value }),
+)
+
+// CSVWriter streams values of T as CSV rows
+type CSVWriter[T any] struct {
+	w       *csv.Writer
+	columns []csvColumn[T]
+	record  []string
+}
+
+// NewCSVWriter creates a writer for the given schema and writes the header row.
+// If no columns are given, all schema columns are written in their default order.
+func NewCSVWriter[T any](w io.Writer, schema *CSVSchema[T], columns ...string) (*CSVWriter[T], error) {
+	if len(columns) == 0 {
+		columns = schema.Columns()
+	}
+	cols, err := schema.lookup(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return nil, fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	return &CSVWriter[T]{
+		w:       cw,
+		columns: cols,
+		record:  make([]string, len(cols)),
+	}, nil
+}
+
+// Write writes a single row. Rows are buffered until Flush is called.
+func (w *CSVWriter[T]) Write(v T) error {
+	for i, col := range w.columns {
+		w.record[i] = col.get(&v)
+	}
+	if err := w.w.Write(w.record); err != nil {
+		return fmt.Errorf("failed to write csv row: %w", err)
+	}
+	return nil
+}
+
+// WriteAll writes all rows and flushes the writer
+func (w *CSVWriter[T]) WriteAll(values []T) error {
+	for _, v := range values {
+		if err := w.Write(v); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Flush writes any buffered rows to the underlying writer
+func (w *CSVWriter[T]) Flush() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// CSVReader streams values of T from CSV rows
+type CSVReader[T any] struct {
+	r       *csv.Reader
+	columns []csvColumn[T]
+	line    int
+}
+
+// NewCSVReader creates a reader for the given schema and consumes the header row.
+// The header may contain any subset of the schema columns in any order;
+// fields without a column are left at their zero value.
+func NewCSVReader[T any](r io.Reader, schema *CSVSchema[T]) (*CSVReader[T], error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+	cols, err := schema.lookup(header)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CSVReader[T]{
+		r:       cr,
+		columns: cols,
+		line:    1,
+	}, nil
+}
+
+// Read returns the next row, or io.EOF when there are no more rows
+func (r *CSVReader[T]) Read() (T, error) {
+	var v T
+	record, err := r.r.Read()
+	if err != nil {
+		if err == io.EOF {
+			return v, io.EOF
+		}
+		return v, fmt.Errorf("failed to read csv row: %w", err)
+	}
+	r.line++
+
+	for i, col := range r.columns {
+		if err := col.set(&v, record[i]); err != nil {
+			return v, fmt.Errorf("invalid value for column %q on line %d: %w", col.name, r.line, err)
+		}
+	}
+	return v, nil
+}
+
+// ReadAll reads all remaining rows
+func (r *CSVReader[T]) ReadAll() ([]T, error) {
+	var values []T
+	for {
+		v, err := r.Read()
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+}
@@ -0,0 +1,133 @@
+package polymarketdata
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// csvRoundTrip writes values with schema and reads them back
+func csvRoundTrip[T any](t *testing.T, schema *CSVSchema[T], values []T, columns ...string) ([]T, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewCSVWriter(&buf, schema, columns...)
+	if err != nil {
+		t.Fatalf("NewCSVWriter failed: %v", err)
+	}
+	if err := w.WriteAll(values); err != nil {
+		t.Fatalf("WriteAll failed: %v", err)
+	}
+	out := buf.String()
+
+	r, err := NewCSVReader(strings.NewReader(out), schema)
+	if err != nil {
+		t.Fatalf("NewCSVReader failed: %v", err)
+	}
+	got, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	return got, out
+}
+
+func assertSameJSON(t *testing.T, want, got any) {
+	t.Helper()
+	a, _ := json.Marshal(want)
+	b, _ := json.Marshal(got)
+	if !bytes.Equal(a, b) {
+		t.Errorf("round trip mismatch:\nwant %s\ngot  %s", a, b)
+	}
+}
+
+func TestCSVRoundTripTrades(t *testing.T) {
+	var trades []Trade
+	if err := json.Unmarshal([]byte(tradesFixture), &trades); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	trades[0].Title = `Quotes "and", commas`
+	trades[0].Price = decimal.RequireFromString("0.123456789012345678901234567890")
+
+	got, _ := csvRoundTrip(t, TradeCSV, trades)
+	assertSameJSON(t, trades, got)
+	if !got[0].Price.Equal(trades[0].Price) {
+		t.Errorf("price precision lost: %s", got[0].Price)
+	}
+}
+
+func TestCSVRoundTripAllSchemas(t *testing.T) {
+	positions := []Position{{
+		ProxyWallet:  "0x1",
+		Asset:        "a",
+		Size:         decimal.RequireFromString("12.5"),
+		AvgPrice:     decimal.RequireFromString("0.333333"),
+		Redeemable:   true,
+		MarketRef:    MarketRef{Title: "T", Outcome: "Yes"},
+		OutcomeIndex: 1,
+		NegativeRisk: true,
+	}}
+	got, _ := csvRoundTrip(t, PositionCSV, positions)
+	assertSameJSON(t, positions, got)
+
+	closed := []ClosedPosition{{ProxyWallet: "0x1", RealizedPnl: decimal.RequireFromString("-4.2"), EndDate: "2025-01-01"}}
+	gotClosed, _ := csvRoundTrip(t, ClosedPositionCSV, closed)
+	assertSameJSON(t, closed, gotClosed)
+
+	activity := []Activity{{ProxyWallet: "0x1", Type: ActivityTypeRedeem, UsdcSize: decimal.RequireFromString("99.000001"), UserProfile: UserProfile{Bio: "line\nbreak"}}}
+	gotActivity, _ := csvRoundTrip(t, ActivityCSV, activity)
+	assertSameJSON(t, activity, gotActivity)
+
+	values := []UserValue{{User: "0x1", Value: decimal.RequireFromString("1.01")}}
+	gotValues, _ := csvRoundTrip(t, UserValueCSV, values)
+	assertSameJSON(t, values, gotValues)
+
+	oi := []OpenInterest{{Market: "0xc", Value: decimal.RequireFromString("1000000.000001")}}
+	gotOI, _ := csvRoundTrip(t, OpenInterestCSV, oi)
+	assertSameJSON(t, oi, gotOI)
+}
+
+func TestCSVRoundTripFlattenedTypes(t *testing.T) {
+	holders := []MarketHolders{
+		{Token: "t1", Holders: []Holder{{ProxyWallet: "0x1", Amount: decimal.NewFromInt(5)}, {ProxyWallet: "0x2", Amount: decimal.NewFromInt(3)}}},
+		{Token: "t2", Holders: []Holder{{ProxyWallet: "0x3", OutcomeIndex: 1, UserProfile: UserProfile{Name: "n"}}}},
+	}
+	rows, _ := csvRoundTrip(t, HolderCSV, FlattenHolders(holders))
+	assertSameJSON(t, holders, GroupHolders(rows))
+
+	volumes := []LiveVolume{
+		{Total: decimal.RequireFromString("10.5"), Markets: []LiveVolumeMarket{{Market: "0xa", Value: decimal.RequireFromString("4")}, {Market: "0xb", Value: decimal.RequireFromString("6.5")}}},
+		{Total: decimal.Zero},
+	}
+	volumeRows, _ := csvRoundTrip(t, LiveVolumeCSV, FlattenLiveVolume(volumes))
+	assertSameJSON(t, volumes, GroupLiveVolume(volumeRows))
+}
+
+func TestCSVColumnSelection(t *testing.T) {
+	values := []OpenInterest{{Market: "0xc", Value: decimal.RequireFromString("2")}}
+	got, out := csvRoundTrip(t, OpenInterestCSV, values, "value", "market")
+	if !strings.HasPrefix(out, "value,market\n2,0xc\n") {
+		t.Errorf("unexpected output: %q", out)
+	}
+	assertSameJSON(t, values, got)
+
+	got, _ = csvRoundTrip(t, OpenInterestCSV, values, "market")
+	if got[0].Market != "0xc" || !got[0].Value.IsZero() {
+		t.Errorf("partial columns = %+v", got[0])
+	}
+
+	if _, err := NewCSVWriter(&bytes.Buffer{}, OpenInterestCSV, "nope"); err == nil {
+		t.Error("expected error for unknown column")
+	}
+	if _, err := NewCSVReader(strings.NewReader("market,market\n"), OpenInterestCSV); err == nil {
+		t.Error("expected error for duplicate column")
+	}
+	r, err := NewCSVReader(strings.NewReader("market,value\n0xc,abc\n"), OpenInterestCSV)
+	if err != nil {
+		t.Fatalf("NewCSVReader failed: %v", err)
+	}
+	if _, err := r.Read(); err == nil {
+		t.Error("expected error for invalid decimal")
+	}
+}
@@ -0,0 +1,204 @@
+package polymarketdata
+
+import (
+	"strings"
+	"sync"
+)
+
+// MarketInfo describes a market (condition) as learned from API responses
+type MarketInfo struct {
+	ConditionId string
+	Title       string
+	Slug        string
+	Icon        string
+	EventSlug   string
+	Outcomes    map[int]string // Outcome name by outcome index
+	Assets      map[int]string // Token ID by outcome index
+}
+
+// AssetInfo describes a single outcome token as learned from API responses
+type AssetInfo struct {
+	Asset        string
+	ConditionId  string
+	OutcomeIndex int
+	Outcome      string
+}
+
+// Directory is a concurrency-safe cache of user profiles and market metadata.
+// The client feeds it from every response it decodes, so display names and
+// market titles can be looked up without another API call.
+type Directory struct {
+	mu       sync.RWMutex
+	profiles map[string]UserProfile
+	markets  map[string]*MarketInfo
+	assets   map[string]AssetInfo
+}
+
+// NewDirectory creates an empty directory
+func NewDirectory() *Directory {
+	return &Directory{
+		profiles: make(map[string]UserProfile),
+		markets:  make(map[string]*MarketInfo),
+		assets:   make(map[string]AssetInfo),
+	}
+}
+
+// Profile returns the last known profile for a wallet address
+func (d *Directory) Profile(wallet string) (UserProfile, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	p, ok := d.profiles[strings.ToLower(wallet)]
+	return p, ok
+}
+
+// DisplayName returns the wallet's name or pseudonym, or the address itself if unknown
+func (d *Directory) DisplayName(wallet string) string {
+	if p, ok := d.Profile(wallet); ok {
+		if name := p.DisplayName(); name != "" {
+			return name
+		}
+	}
+	return wallet
+}
+
+// Market returns the last known metadata for a condition ID
+func (d *Directory) Market(conditionId string) (MarketInfo, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	m, ok := d.markets[strings.ToLower(conditionId)]
+	if !ok {
+		return MarketInfo{}, false
+	}
+	info := *m
+	info.Outcomes = make(map[int]string, len(m.Outcomes))
+	for k, v := range m.Outcomes {
+		info.Outcomes[k] = v
+	}
+	info.Assets = make(map[int]string, len(m.Assets))
+	for k, v := range m.Assets {
+		info.Assets[k] = v
+	}
+	return info, true
+}
+
+// Asset returns the last known metadata for an outcome token
+func (d *Directory) Asset(asset string) (AssetInfo, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	a, ok := d.assets[asset]
+	return a, ok
+}
+
+// Len returns the number of known profiles and markets
+func (d *Directory) Len() (profiles, markets int) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.profiles), len(d.markets)
+}
+
+// ObservePositions records market metadata from positions
+func (d *Directory) ObservePositions(positions []Position) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, p := range positions {
+		d.learnMarket(p.ConditionId, p.Asset, p.OutcomeIndex, p.MarketRef)
+		if p.OppositeAsset != "" {
+			d.learnMarket(p.ConditionId, p.OppositeAsset, 1-p.OutcomeIndex, MarketRef{Outcome: p.OppositeOutcome})
+		}
+	}
+}
+
+// ObserveClosedPositions records market metadata from closed positions
+func (d *Directory) ObserveClosedPositions(positions []ClosedPosition) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, p := range positions {
+		d.learnMarket(p.ConditionId, p.Asset, p.OutcomeIndex, p.MarketRef)
+		if p.OppositeAsset != "" {
+			d.learnMarket(p.ConditionId, p.OppositeAsset, 1-p.OutcomeIndex, MarketRef{Outcome: p.OppositeOutcome})
+		}
+	}
+}
+
+// ObserveTrades records profiles and market metadata from trades
+func (d *Directory) ObserveTrades(trades []Trade) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, t := range trades {
+		d.learnProfile(t.ProxyWallet, t.UserProfile)
+		d.learnMarket(t.ConditionId, t.Asset, t.OutcomeIndex, t.MarketRef)
+	}
+}
+
+// ObserveActivity records profiles and market metadata from activity
+func (d *Directory) ObserveActivity(activities []Activity) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, a := range activities {
+		d.learnProfile(a.ProxyWallet, a.UserProfile)
+		d.learnMarket(a.ConditionId, a.Asset, a.OutcomeIndex, a.MarketRef)
+	}
+}
+
+// ObserveHolders records profiles from holders
+func (d *Directory) ObserveHolders(holders []MarketHolders) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, mh := range holders {
+		for _, h := range mh.Holders {
+			d.learnProfile(h.ProxyWallet, h.UserProfile)
+		}
+	}
+}
+
+// learnProfile stores a profile, ignoring empty ones so that a sparse
+// response never erases what an earlier one taught us
+func (d *Directory) learnProfile(wallet string, p UserProfile) {
+	if wallet == "" || p == (UserProfile{}) {
+		return
+	}
+	d.profiles[strings.ToLower(wallet)] = p
+}
+
+// learnMarket merges non-empty market fields into the directory
+func (d *Directory) learnMarket(conditionId, asset string, outcomeIndex int, ref MarketRef) {
+	if conditionId == "" {
+		return
+	}
+	key := strings.ToLower(conditionId)
+	m, ok := d.markets[key]
+	if !ok {
+		m = &MarketInfo{
+			ConditionId: conditionId,
+			Outcomes:    make(map[int]string),
+			Assets:      make(map[int]string),
+		}
+		d.markets[key] = m
+	}
+	if ref.Title != "" {
+		m.Title = ref.Title
+	}
+	if ref.Slug != "" {
+		m.Slug = ref.Slug
+	}
+	if ref.Icon != "" {
+		m.Icon = ref.Icon
+	}
+	if ref.EventSlug != "" {
+		m.EventSlug = ref.EventSlug
+	}
+	if ref.Outcome != "" {
+		m.Outcomes[outcomeIndex] = ref.Outcome
+	}
+	if asset != "" {
+		m.Assets[outcomeIndex] = asset
+		info := d.assets[asset]
+		info.Asset = asset
+		info.ConditionId = conditionId
+		info.OutcomeIndex = outcomeIndex
+		if ref.Outcome != "" {
+			info.Outcome = ref.Outcome
+		}
+		d.assets[asset] = info
+	}
+}
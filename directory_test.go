@@ -0,0 +1,132 @@
+package polymarketdata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+const tradesFixture = `[{
+	"proxyWallet": "0xAbC0000000000000000000000000000000000001",
+	"side": "BUY",
+	"asset": "111",
+	"conditionId": "0xcond",
+	"size": "10",
+	"price": "0.45",
+	"timestamp": 1700000000,
+	"title": "Will it rain?",
+	"slug": "will-it-rain",
+	"icon": "https://example.com/icon.png",
+	"eventSlug": "weather",
+	"outcome": "Yes",
+	"outcomeIndex": 0,
+	"name": "alice",
+	"pseudonym": "Quiet-Fox",
+	"bio": "",
+	"profileImage": "",
+	"profileImageOptimized": "",
+	"transactionHash": "0xtx"
+}]`
+
+func TestEmbeddedTypesKeepJSONShape(t *testing.T) {
+	var trades []Trade
+	if err := json.Unmarshal([]byte(tradesFixture), &trades); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if trades[0].Title != "Will it rain?" || trades[0].Name != "alice" || trades[0].Outcome != "Yes" {
+		t.Fatalf("embedded fields not decoded: %+v", trades[0])
+	}
+
+	out, err := json.Marshal(trades[0])
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var flat map[string]any
+	if err := json.Unmarshal(out, &flat); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	for _, key := range []string{"title", "slug", "icon", "eventSlug", "outcome", "name", "pseudonym", "bio", "profileImage", "profileImageOptimized"} {
+		if _, ok := flat[key]; !ok {
+			t.Errorf("key %q missing from flattened JSON", key)
+		}
+	}
+	if _, ok := flat["MarketRef"]; ok {
+		t.Errorf("embedded struct leaked into JSON: %s", out)
+	}
+}
+
+func TestClientFeedsDirectory(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(tradesFixture))
+	}))
+
+	if _, err := client.GetTrades(context.Background(), &GetTradesParams{}); err != nil {
+		t.Fatalf("GetTrades failed: %v", err)
+	}
+
+	dir := client.Directory()
+	if got := dir.DisplayName("0xabc0000000000000000000000000000000000001"); got != "alice" {
+		t.Errorf("DisplayName = %q, want alice", got)
+	}
+	market, ok := dir.Market("0xcond")
+	if !ok || market.Title != "Will it rain?" || market.Outcomes[0] != "Yes" || market.Assets[0] != "111" {
+		t.Errorf("Market = %+v, %v", market, ok)
+	}
+	asset, ok := dir.Asset("111")
+	if !ok || asset.ConditionId != "0xcond" || asset.Outcome != "Yes" {
+		t.Errorf("Asset = %+v, %v", asset, ok)
+	}
+}
+
+func TestZeroValueClientFeedsDirectory(t *testing.T) {
+	client := &Client{httpClient: &http.Client{Transport: handlerTransport{handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(tradesFixture))
+	})}}}
+
+	if _, err := client.GetTrades(context.Background(), &GetTradesParams{}); err != nil {
+		t.Fatalf("GetTrades failed: %v", err)
+	}
+	if got := client.Directory().DisplayName("0xabc0000000000000000000000000000000000001"); got != "alice" {
+		t.Errorf("DisplayName = %q, want alice", got)
+	}
+}
+
+func TestDirectoryKeepsKnownFields(t *testing.T) {
+	dir := NewDirectory()
+	dir.ObserveActivity([]Activity{{
+		ProxyWallet: "0x1",
+		ConditionId: "0xc",
+		Asset:       "a",
+		MarketRef:   MarketRef{Title: "Title", Outcome: "No"},
+		UserProfile: UserProfile{Pseudonym: "Fox"},
+	}})
+	dir.ObserveHolders([]MarketHolders{{Token: "a", Holders: []Holder{{ProxyWallet: "0x1"}}}})
+	dir.ObservePositions([]Position{{ConditionId: "0xc", Asset: "b", OutcomeIndex: 1}})
+
+	if p, ok := dir.Profile("0x1"); !ok || p.Pseudonym != "Fox" {
+		t.Errorf("empty profile overwrote known one: %+v", p)
+	}
+	if m, _ := dir.Market("0xc"); m.Title != "Title" {
+		t.Errorf("empty title overwrote known one: %+v", m)
+	}
+}
+
+func TestDirectoryConcurrentUse(t *testing.T) {
+	dir := NewDirectory()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			dir.ObserveTrades([]Trade{{ProxyWallet: "0x1", ConditionId: "0xc", UserProfile: UserProfile{Name: "n"}}})
+		}()
+		go func() {
+			defer wg.Done()
+			dir.Profile("0x1")
+			dir.Market("0xc")
+		}()
+	}
+	wg.Wait()
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/ivanzzeth/polymarket-go-data-client/orderflow"
 	"github.com/shopspring/decimal"
 )
 
@@ -17,7 +18,7 @@ type SentimentSignal struct {
 	BuyPressure         decimal.Decimal
 	SellPressure        decimal.Decimal
 	HolderConcentration decimal.Decimal
-	SignalStrength      string
+	SignalStrength      orderflow.Strength
 	Reasoning           string
 }
 
@@ -73,9 +74,9 @@ func main() {
 	moderateSignals := 0
 
 	for _, signal := range signals {
-		if signal.SignalStrength == "STRONG" {
+		if signal.SignalStrength == orderflow.StrengthStrong {
 			strongSignals++
-		} else if signal.SignalStrength == "MODERATE" {
+		} else if signal.SignalStrength == orderflow.StrengthModerate {
 			moderateSignals++
 		}
 	}
@@ -109,28 +110,18 @@ func detectSentimentReversal(client *polymarketdata.Client, marketId string) (Se
 
 	signal.MarketTitle = trades[0].Title
 
-	// Calculate buy/sell pressure
-	buyCount := 0
-	sellCount := 0
-	buyVolume := decimal.Zero
-	sellVolume := decimal.Zero
-
-	for _, trade := range trades {
-		tradeVolume := trade.Size.Mul(trade.Price)
-		if trade.Side == polymarketdata.TradeSideBuy {
-			buyCount++
-			buyVolume = buyVolume.Add(tradeVolume)
-		} else {
-			sellCount++
-			sellVolume = sellVolume.Add(tradeVolume)
-		}
+	// Calculate buy/sell pressure on the first outcome. Buying the other
+	// outcome counts as selling pressure, not buying pressure.
+	flow, err := orderflow.Analyze(trades, nil)
+	if err != nil {
+		return signal, fmt.Errorf("failed to analyze order flow: %w", err)
 	}
-
-	totalVolume := buyVolume.Add(sellVolume)
-	if !totalVolume.IsZero() {
-		signal.BuyPressure = buyVolume.Div(totalVolume).Mul(decimal.NewFromInt(100))
-		signal.SellPressure = sellVolume.Div(totalVolume).Mul(decimal.NewFromInt(100))
+	market := orderflow.MarketFlow{Signal: orderflow.Signal{Strength: orderflow.StrengthWeak}}
+	if len(flow.Markets) > 0 {
+		market = flow.Markets[0]
 	}
+	signal.BuyPressure = market.BullishPressure.Mul(decimal.NewFromInt(100))
+	signal.SellPressure = market.BearishPressure.Mul(decimal.NewFromInt(100))
 
 	// Get holder concentration
 	holders, err := client.GetHolders(context.Background(), &polymarketdata.GetHoldersParams{
@@ -156,36 +147,14 @@ func detectSentimentReversal(client *polymarketdata.Client, marketId string) (Se
 		}
 	}
 
-	// Determine signal strength and reasoning
-	signal.SignalStrength = "WEAK"
-	signal.Reasoning = "Market conditions are relatively balanced"
-
-	// Strong buy-side crowding (>85% buy pressure)
-	if signal.BuyPressure.GreaterThan(decimal.NewFromInt(85)) {
-		signal.SignalStrength = "STRONG"
-		signal.Reasoning = "Extreme buy-side crowding detected! >85% of recent volume is buys. " +
-			"Market may be overbought and vulnerable to reversal. Consider selling or waiting for pullback."
-	} else if signal.BuyPressure.GreaterThan(decimal.NewFromInt(75)) {
-		signal.SignalStrength = "MODERATE"
-		signal.Reasoning = "Significant buy-side pressure (>75%). Market is getting crowded on one side. " +
-			"Watch for signs of exhaustion or negative catalysts."
-	}
-
-	// Strong sell-side crowding (>85% sell pressure)
-	if signal.SellPressure.GreaterThan(decimal.NewFromInt(85)) {
-		signal.SignalStrength = "STRONG"
-		signal.Reasoning = "Extreme sell-side crowding detected! >85% of recent volume is sells. " +
-			"Market may be oversold and vulnerable to reversal. Consider buying the dip."
-	} else if signal.SellPressure.GreaterThan(decimal.NewFromInt(75)) {
-		signal.SignalStrength = "MODERATE"
-		signal.Reasoning = "Significant sell-side pressure (>75%). Market is getting crowded on one side. " +
-			"Watch for signs of capitulation or positive catalysts."
-	}
+	// Signal strength comes from the order flow classification against orderflow.DefaultThresholds
+	signal.SignalStrength = market.Signal.Strength
+	signal.Reasoning = reasoning(market.Signal)
 
 	// Add holder concentration risk
 	if signal.HolderConcentration.GreaterThan(decimal.NewFromInt(70)) {
-		if signal.SignalStrength == "WEAK" {
-			signal.SignalStrength = "MODERATE"
+		if signal.SignalStrength == orderflow.StrengthWeak {
+			signal.SignalStrength = orderflow.StrengthModerate
 		}
 		signal.Reasoning += " Additionally, top 10 holders control >70% of positions - " +
 			"high concentration risk. Large holder movements could trigger sharp price changes."
@@ -194,6 +163,24 @@ func detectSentimentReversal(client *polymarketdata.Client, marketId string) (Se
 	return signal, nil
 }
 
+// reasoning explains an order flow signal and the threshold it crossed
+func reasoning(s orderflow.Signal) string {
+	threshold := orderflow.DefaultThresholds.Moderate
+	switch s.Strength {
+	case orderflow.StrengthWeak:
+		return "Market conditions are relatively balanced"
+	case orderflow.StrengthStrong:
+		threshold = orderflow.DefaultThresholds.Strong
+	}
+
+	crowd, outlook := "buy", "overbought and vulnerable to reversal. Consider selling or waiting for a pullback."
+	if s.Side == polymarketdata.TradeSideSell {
+		crowd, outlook = "sell", "oversold and vulnerable to reversal. Consider buying the dip."
+	}
+	return fmt.Sprintf("%s %s-side crowding: %s%% of recent volume is on the %s side, above the %s%% threshold. Market may be %s",
+		s.Strength, crowd, s.Pressure.Shift(2).StringFixed(1), crowd, threshold.Shift(2).String(), outlook)
+}
+
 func showDetailedAnalysis(client *polymarketdata.Client, marketId string) {
 	// Get recent trading patterns
 	trades, err := client.GetTrades(context.Background(), &polymarketdata.GetTradesParams{
@@ -8,6 +8,7 @@ import (
 	"sort"
 
 	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/ivanzzeth/polymarket-go-data-client/concentration"
 	"github.com/shopspring/decimal"
 )
 
@@ -122,23 +123,18 @@ func main() {
 		}
 
 		// Calculate concentration metrics
-		top3Amount := decimal.Zero
-		top10Amount := decimal.Zero
-		for i, whale := range whales {
-			if i < 3 {
-				top3Amount = top3Amount.Add(whale.Amount)
-			}
-			if i < 10 {
-				top10Amount = top10Amount.Add(whale.Amount)
-			}
-		}
-
-		top3Pct := top3Amount.Div(totalAmount).Mul(decimal.NewFromInt(100))
-		top10Pct := top10Amount.Div(totalAmount).Mul(decimal.NewFromInt(100))
+		metrics := concentration.Measure(marketHolder, &concentration.Options{Limit: 20, MinBalance: 1000})
+		top3Pct := metrics.Share(3).Mul(decimal.NewFromInt(100))
+		top10Pct := metrics.Share(10).Mul(decimal.NewFromInt(100))
 
 		fmt.Printf("\n=== Concentration Metrics ===\n")
+		if metrics.Truncated {
+			fmt.Println("(Only the top 20 holders are listed, so shares are of the listed tokens)")
+		}
 		fmt.Printf("Top 3 holders control: %.2f%% of tokens\n", top3Pct.InexactFloat64())
 		fmt.Printf("Top 10 holders control: %.2f%% of tokens\n", top10Pct.InexactFloat64())
+		fmt.Printf("HHI: %.0f, Gini: %.2f, Nakamoto coefficient: %d\n",
+			metrics.HHI.Mul(decimal.NewFromInt(10000)).InexactFloat64(), metrics.Gini.InexactFloat64(), metrics.Nakamoto)
 
 		if top3Pct.GreaterThan(decimal.NewFromInt(50)) {
 			fmt.Println("⚠️  WARNING: High concentration! Top 3 holders control >50% of tokens")
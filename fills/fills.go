@@ -0,0 +1,136 @@
+// Package fills merges the individual fills returned by GetTrades into the
+// logical orders they belong to.
+package fills
+
+import (
+	"sort"
+	"strings"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// precision is the number of decimal places kept by divisions
+const precision = 16
+
+// Order is a set of fills sharing a transaction hash, wallet, asset and side
+type Order struct {
+	TransactionHash string
+	ProxyWallet     string
+	Asset           string
+	ConditionId     string
+	OutcomeIndex    int
+	Outcome         string
+	Title           string
+	Side            polymarketdata.TradeSide
+	Timestamp       int64                  // Timestamp of the first fill
+	Fills           []polymarketdata.Trade // In fill order
+	Size            decimal.Decimal        // Total tokens filled
+	Notional        decimal.Decimal        // Sum of Size × Price
+	VWAP            decimal.Decimal        // Notional / Size
+	FirstPrice      decimal.Decimal
+	LastPrice       decimal.Decimal
+	MinPrice        decimal.Decimal
+	MaxPrice        decimal.Decimal
+	// Slippage is how much worse the VWAP is than the first fill's price, in price
+	// units: VWAP - FirstPrice for buys and FirstPrice - VWAP for sells.
+	// Negative when later fills improved the price.
+	Slippage    decimal.Decimal
+	SlippagePct decimal.Decimal // Slippage / FirstPrice
+}
+
+// FillCount returns the number of fills in the order
+func (o Order) FillCount() int {
+	return len(o.Fills)
+}
+
+// PriceRange returns MaxPrice - MinPrice
+func (o Order) PriceRange() decimal.Decimal {
+	return o.MaxPrice.Sub(o.MinPrice)
+}
+
+// Key returns the grouping key of a fill. Fills without a transaction hash
+// cannot be grouped and get a key of their own.
+func Key(t polymarketdata.Trade) string {
+	if t.TransactionHash == "" {
+		return polymarketdata.TradeKey(t)
+	}
+	return strings.ToLower(t.TransactionHash) + "|" + strings.ToLower(t.ProxyWallet) + "|" + t.Asset + "|" + string(t.Side)
+}
+
+// Group merges fills into orders. Fills are ordered by timestamp, keeping the
+// input order for equal timestamps, and the first fill is the earliest one.
+// Orders are returned in the order of their first fill.
+func Group(trades []polymarketdata.Trade) []Order {
+	sorted := make([]polymarketdata.Trade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp < sorted[j].Timestamp
+	})
+
+	index := make(map[string]int)
+	var orders []Order
+	for _, t := range sorted {
+		key := Key(t)
+		i, ok := index[key]
+		if !ok {
+			i = len(orders)
+			index[key] = i
+			orders = append(orders, Order{
+				TransactionHash: t.TransactionHash,
+				ProxyWallet:     t.ProxyWallet,
+				Asset:           t.Asset,
+				ConditionId:     t.ConditionId,
+				OutcomeIndex:    t.OutcomeIndex,
+				Outcome:         t.Outcome,
+				Title:           t.Title,
+				Side:            t.Side,
+				Timestamp:       t.Timestamp,
+				FirstPrice:      t.Price,
+				MinPrice:        t.Price,
+				MaxPrice:        t.Price,
+			})
+		}
+
+		o := &orders[i]
+		o.Fills = append(o.Fills, t)
+		o.Size = o.Size.Add(t.Size)
+		o.Notional = o.Notional.Add(t.Size.Mul(t.Price))
+		o.LastPrice = t.Price
+		if t.Price.LessThan(o.MinPrice) {
+			o.MinPrice = t.Price
+		}
+		if t.Price.GreaterThan(o.MaxPrice) {
+			o.MaxPrice = t.Price
+		}
+	}
+
+	for i := range orders {
+		o := &orders[i]
+		if !o.Size.IsPositive() {
+			continue
+		}
+		o.VWAP = o.Notional.DivRound(o.Size, precision)
+		o.Slippage = o.VWAP.Sub(o.FirstPrice)
+		if o.Side == polymarketdata.TradeSideSell {
+			o.Slippage = o.Slippage.Neg()
+		}
+		if o.FirstPrice.IsPositive() {
+			o.SlippagePct = o.Slippage.DivRound(o.FirstPrice, precision)
+		}
+	}
+	return orders
+}
+
+// Trades converts orders back into one trade per order, with the total size
+// and VWAP price, so order-level data can feed code that expects trades
+func Trades(orders []Order) []polymarketdata.Trade {
+	out := make([]polymarketdata.Trade, 0, len(orders))
+	for _, o := range orders {
+		t := o.Fills[0]
+		t.Size = o.Size
+		t.Price = o.VWAP
+		out = append(out, t)
+	}
+	return out
+}
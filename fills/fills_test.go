@@ -0,0 +1,90 @@
+package fills
+
+import (
+	"testing"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func fill(tx, wallet, asset string, side polymarketdata.TradeSide, ts int64, size, price string) polymarketdata.Trade {
+	return polymarketdata.Trade{
+		TransactionHash: tx,
+		ProxyWallet:     wallet,
+		Asset:           asset,
+		Side:            side,
+		Timestamp:       ts,
+		Size:            decimal.RequireFromString(size),
+		Price:           decimal.RequireFromString(price),
+	}
+}
+
+func expectDecimal(t *testing.T, name string, got decimal.Decimal, want string) {
+	t.Helper()
+	if !got.Round(8).Equal(decimal.RequireFromString(want)) {
+		t.Errorf("%s: got %s, want %s", name, got, want)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	trades := []polymarketdata.Trade{
+		fill("0xT1", "0xw", "yes", polymarketdata.TradeSideBuy, 100, "100", "0.50"),
+		fill("0xt2", "0xw", "yes", polymarketdata.TradeSideSell, 100, "10", "0.60"),
+		fill("0xt1", "0xW", "yes", polymarketdata.TradeSideBuy, 100, "200", "0.52"),
+		fill("0xt1", "0xw", "no", polymarketdata.TradeSideBuy, 100, "5", "0.48"),
+		fill("0xt2", "0xw", "yes", polymarketdata.TradeSideSell, 100, "30", "0.56"),
+		fill("0xt1", "0xw", "yes", polymarketdata.TradeSideBuy, 100, "100", "0.54"),
+		fill("", "0xw", "yes", polymarketdata.TradeSideBuy, 50, "1", "0.40"),
+	}
+
+	orders := Group(trades)
+	if len(orders) != 4 {
+		t.Fatalf("expected 4 orders, got %d", len(orders))
+	}
+
+	// The fill without a transaction hash is earliest and stands alone
+	if orders[0].TransactionHash != "" || orders[0].FillCount() != 1 {
+		t.Errorf("unexpected first order %+v", orders[0])
+	}
+
+	buy := orders[1]
+	if buy.Asset != "yes" || buy.Side != polymarketdata.TradeSideBuy || buy.FillCount() != 3 {
+		t.Fatalf("unexpected buy order %+v", buy)
+	}
+	expectDecimal(t, "size", buy.Size, "400")
+	expectDecimal(t, "notional", buy.Notional, "208")
+	expectDecimal(t, "vwap", buy.VWAP, "0.52")
+	expectDecimal(t, "first price", buy.FirstPrice, "0.5")
+	expectDecimal(t, "last price", buy.LastPrice, "0.54")
+	expectDecimal(t, "range", buy.PriceRange(), "0.04")
+	expectDecimal(t, "slippage", buy.Slippage, "0.02")
+	expectDecimal(t, "slippage pct", buy.SlippagePct, "0.04")
+
+	sell := orders[2]
+	if sell.Side != polymarketdata.TradeSideSell || sell.FillCount() != 2 {
+		t.Fatalf("unexpected sell order %+v", sell)
+	}
+	// Selling at falling prices is adverse slippage
+	expectDecimal(t, "sell vwap", sell.VWAP, "0.57")
+	expectDecimal(t, "sell slippage", sell.Slippage, "0.03")
+
+	if orders[3].Asset != "no" || orders[3].FillCount() != 1 {
+		t.Errorf("expected the other asset to be a separate order, got %+v", orders[3])
+	}
+}
+
+func TestTrades(t *testing.T) {
+	orders := Group([]polymarketdata.Trade{
+		fill("0xt", "0xw", "yes", polymarketdata.TradeSideBuy, 1, "10", "0.4"),
+		fill("0xt", "0xw", "yes", polymarketdata.TradeSideBuy, 1, "30", "0.6"),
+	})
+	trades := Trades(orders)
+	if len(trades) != 1 {
+		t.Fatalf("expected 1 trade, got %d", len(trades))
+	}
+	expectDecimal(t, "size", trades[0].Size, "40")
+	expectDecimal(t, "price", trades[0].Price, "0.55")
+	if trades[0].TransactionHash != "0xt" {
+		t.Errorf("expected trade fields to be kept, got %+v", trades[0])
+	}
+}
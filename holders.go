@@ -67,5 +67,7 @@ func (c *Client) GetHolders(ctx context.Context, params *GetHoldersParams) ([]Ma
 		return nil, fmt.Errorf("failed to decode holders response: %w", err)
 	}
 
+	c.Directory().ObserveHolders(holders)
+
 	return holders, nil
 }
@@ -0,0 +1,392 @@
+// Package leaderboard ranks a tracked set of wallets by PnL, value, markets
+// traded, volume or a custom score, with rank and score changes between runs.
+package leaderboard
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultWindow is the period volume is measured over when no window is configured
+const DefaultWindow = 24 * time.Hour
+
+// DefaultCacheTTL is how long fetched data is reused when no TTL is configured
+const DefaultCacheTTL = 5 * time.Minute
+
+// DefaultConcurrency is the number of wallets fetched at once when no concurrency is configured
+const DefaultConcurrency = 4
+
+// Entry holds the figures of a single wallet
+type Entry struct {
+	User            string
+	Name            string          // Display name, if known
+	RealizedPnl     decimal.Decimal // Closed positions plus the realized part of open positions
+	UnrealizedPnl   decimal.Decimal // Cash PnL of open positions
+	TotalPnl        decimal.Decimal // RealizedPnl + UnrealizedPnl
+	Value           decimal.Decimal // Current value of open positions
+	MarketsTraded   int
+	Volume          decimal.Decimal // Cash traded within the window
+	Trades          int             // Trades within the window
+	OpenPositions   int
+	ClosedPositions int
+}
+
+// ScoreFunc computes the value wallets are ranked by, highest first
+type ScoreFunc func(Entry) decimal.Decimal
+
+// Built-in score functions
+var (
+	ByRealizedPnl   ScoreFunc = func(e Entry) decimal.Decimal { return e.RealizedPnl }
+	ByUnrealizedPnl ScoreFunc = func(e Entry) decimal.Decimal { return e.UnrealizedPnl }
+	ByTotalPnl      ScoreFunc = func(e Entry) decimal.Decimal { return e.TotalPnl }
+	ByValue         ScoreFunc = func(e Entry) decimal.Decimal { return e.Value }
+	ByVolume        ScoreFunc = func(e Entry) decimal.Decimal { return e.Volume }
+	ByMarketsTraded ScoreFunc = func(e Entry) decimal.Decimal { return decimal.NewFromInt(int64(e.MarketsTraded)) }
+)
+
+// Options configures a Builder
+type Options struct {
+	Score       ScoreFunc     // Optional: Default ByTotalPnl
+	Window      time.Duration // Optional: Volume is measured over this period before the run. Default DefaultWindow
+	Concurrency int           // Optional: Wallets fetched at once. Default DefaultConcurrency
+	CacheTTL    time.Duration // Optional: Fetched data is reused for this long. Default DefaultCacheTTL, negative disables caching
+}
+
+// Row is a ranked wallet
+type Row struct {
+	Rank         int // Starts at 1. Wallets with equal scores share a rank
+	Score        decimal.Decimal
+	PreviousRank int             // 0 if the wallet was not ranked in the previous run
+	RankChange   int             // PreviousRank - Rank: positive when the wallet moved up
+	ScoreChange  decimal.Decimal // Score - previous score
+	New          bool            // The wallet was not ranked in the previous run
+	Entry
+}
+
+// Leaderboard is the result of a single run
+type Leaderboard struct {
+	GeneratedAt time.Time
+	Rows        []Row
+	Errors      map[string]error // Wallets that could not be fetched, by address
+}
+
+// Builder fetches wallet data and builds leaderboards. It remembers the
+// previous leaderboard to report changes, and caches fetched data between runs.
+type Builder struct {
+	client *polymarketdata.Client
+	opts   Options
+
+	mu       sync.Mutex
+	cache    map[string]cacheItem
+	previous *Leaderboard
+	now      func() time.Time
+}
+
+type cacheItem struct {
+	value     any
+	fetchedAt time.Time
+}
+
+// NewBuilder creates a leaderboard builder
+func NewBuilder(client *polymarketdata.Client, opts *Options) (*Builder, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	b := &Builder{
+		client: client,
+		cache:  make(map[string]cacheItem),
+		now:    time.Now,
+	}
+	if opts != nil {
+		b.opts = *opts
+	}
+	if b.opts.Score == nil {
+		b.opts.Score = ByTotalPnl
+	}
+	if b.opts.Window <= 0 {
+		b.opts.Window = DefaultWindow
+	}
+	if b.opts.Concurrency <= 0 {
+		b.opts.Concurrency = DefaultConcurrency
+	}
+	if b.opts.CacheTTL == 0 {
+		b.opts.CacheTTL = DefaultCacheTTL
+	}
+	return b, nil
+}
+
+// Build fetches every wallet and ranks them. Wallets that fail are left out of
+// the ranking and reported in Errors. The result becomes the baseline for the
+// changes reported by the next run.
+func (b *Builder) Build(ctx context.Context, wallets []string) (*Leaderboard, error) {
+	now := b.now()
+	entries := make([]*Entry, len(wallets))
+	errs := make([]error, len(wallets))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, b.opts.Concurrency)
+	for i, wallet := range wallets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+			entries[i], errs[i] = b.fetch(ctx, wallet, now)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	board := &Leaderboard{GeneratedAt: now, Errors: make(map[string]error)}
+	var ranked []Entry
+	for i, e := range entries {
+		if errs[i] != nil {
+			board.Errors[wallets[i]] = errs[i]
+			continue
+		}
+		ranked = append(ranked, *e)
+	}
+	board.Rows = Rank(ranked, b.opts.Score)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ApplyDeltas(board, b.previous)
+	b.previous = board
+	return board, nil
+}
+
+// Previous returns the leaderboard of the last run, or nil
+func (b *Builder) Previous() *Leaderboard {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.previous
+}
+
+// SetPrevious sets the baseline for the changes reported by the next run,
+// for example a leaderboard kept from an earlier process
+func (b *Builder) SetPrevious(previous *Leaderboard) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.previous = previous
+}
+
+func (b *Builder) fetch(ctx context.Context, wallet string, now time.Time) (*Entry, error) {
+	e := &Entry{User: wallet}
+
+	closed, err := cached(b, "closed", wallet, now, func() ([]polymarketdata.ClosedPosition, error) {
+		return b.client.GetAllClosedPositions(ctx, &polymarketdata.GetClosedPositionsParams{User: wallet})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch closed positions: %w", err)
+	}
+	positions, err := cached(b, "positions", wallet, now, func() ([]polymarketdata.Position, error) {
+		return b.client.GetAllPositions(ctx, &polymarketdata.GetPositionsParams{User: wallet})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch positions: %w", err)
+	}
+	value, err := cached(b, "value", wallet, now, func() ([]polymarketdata.UserValue, error) {
+		return b.client.GetPositionsValue(ctx, &polymarketdata.GetValueParams{User: wallet})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch positions value: %w", err)
+	}
+	traded, err := cached(b, "traded", wallet, now, func() (*polymarketdata.TradedMarketsCount, error) {
+		return b.client.GetTradedMarketsCount(ctx, &polymarketdata.GetTradedMarketsCountParams{User: wallet})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch traded markets count: %w", err)
+	}
+	activity, err := cached(b, "activity", wallet, now, func() ([]polymarketdata.Activity, error) {
+		return b.client.GetAllActivity(ctx, &polymarketdata.GetActivityParams{
+			User:  wallet,
+			Type:  []polymarketdata.ActivityType{polymarketdata.ActivityTypeTrade},
+			Start: now.Add(-b.opts.Window).Unix(),
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch activity: %w", err)
+	}
+
+	for _, p := range closed {
+		e.RealizedPnl = e.RealizedPnl.Add(p.RealizedPnl)
+	}
+	for _, p := range positions {
+		e.RealizedPnl = e.RealizedPnl.Add(p.RealizedPnl)
+		e.UnrealizedPnl = e.UnrealizedPnl.Add(p.CashPnl)
+	}
+	e.TotalPnl = e.RealizedPnl.Add(e.UnrealizedPnl)
+	e.OpenPositions = len(positions)
+	e.ClosedPositions = len(closed)
+	for _, v := range value {
+		e.Value = e.Value.Add(v.Value)
+	}
+	if traded != nil {
+		e.MarketsTraded = traded.Traded
+	}
+	cutoff := now.Add(-b.opts.Window).Unix()
+	for _, a := range activity {
+		if a.Type == polymarketdata.ActivityTypeTrade && a.Timestamp >= cutoff {
+			e.Volume = e.Volume.Add(a.UsdcSize)
+			e.Trades++
+		}
+	}
+	e.Name = b.client.Directory().DisplayName(wallet)
+	return e, nil
+}
+
+// cached returns the cached result of fetch for a kind of data and wallet,
+// calling fetch if there is none younger than the cache TTL
+func cached[T any](b *Builder, kind, wallet string, now time.Time, fetch func() (T, error)) (T, error) {
+	key := kind + "|" + strings.ToLower(wallet)
+	if b.opts.CacheTTL > 0 {
+		b.mu.Lock()
+		item, ok := b.cache[key]
+		b.mu.Unlock()
+		if ok && now.Sub(item.fetchedAt) < b.opts.CacheTTL {
+			return item.value.(T), nil
+		}
+	}
+
+	v, err := fetch()
+	if err != nil {
+		return v, err
+	}
+	if b.opts.CacheTTL > 0 {
+		b.mu.Lock()
+		b.cache[key] = cacheItem{value: v, fetchedAt: now}
+		b.mu.Unlock()
+	}
+	return v, nil
+}
+
+// Rank orders entries by score, highest first. Ties are broken by wallet
+// address so the order is reproducible regardless of the input order.
+func Rank(entries []Entry, score ScoreFunc) []Row {
+	if score == nil {
+		score = ByTotalPnl
+	}
+	rows := make([]Row, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, Row{Score: score(e), Entry: e, New: true})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if c := rows[i].Score.Cmp(rows[j].Score); c != 0 {
+			return c > 0
+		}
+		return strings.ToLower(rows[i].User) < strings.ToLower(rows[j].User)
+	})
+	for i := range rows {
+		if i > 0 && rows[i].Score.Equal(rows[i-1].Score) {
+			rows[i].Rank = rows[i-1].Rank
+		} else {
+			rows[i].Rank = i + 1
+		}
+	}
+	return rows
+}
+
+// ApplyDeltas fills in the rank and score changes of board relative to previous.
+// A nil previous marks every row as new.
+func ApplyDeltas(board, previous *Leaderboard) {
+	before := make(map[string]Row)
+	if previous != nil {
+		for _, r := range previous.Rows {
+			before[strings.ToLower(r.User)] = r
+		}
+	}
+	for i := range board.Rows {
+		r := &board.Rows[i]
+		prev, ok := before[strings.ToLower(r.User)]
+		r.New = !ok
+		if !ok {
+			r.PreviousRank, r.RankChange, r.ScoreChange = 0, 0, decimal.Zero
+			continue
+		}
+		r.PreviousRank = prev.Rank
+		r.RankChange = prev.Rank - r.Rank
+		r.ScoreChange = r.Score.Sub(prev.Score)
+	}
+}
+
+var csvHeader = []string{
+	"rank", "previous_rank", "rank_change", "user", "name", "score", "score_change",
+	"realized_pnl", "unrealized_pnl", "total_pnl", "value", "markets_traded", "volume", "trades",
+	"open_positions", "closed_positions",
+}
+
+// WriteCSV writes the ranked rows. Previous rank and changes are empty for new wallets.
+func (l *Leaderboard) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	for _, r := range l.Rows {
+		prevRank, rankChange, scoreChange := "", "", ""
+		if !r.New {
+			prevRank = strconv.Itoa(r.PreviousRank)
+			rankChange = strconv.Itoa(r.RankChange)
+			scoreChange = r.ScoreChange.String()
+		}
+		cw.Write([]string{
+			strconv.Itoa(r.Rank), prevRank, rankChange, r.User, r.Name, r.Score.String(), scoreChange,
+			r.RealizedPnl.String(), r.UnrealizedPnl.String(), r.TotalPnl.String(), r.Value.String(),
+			strconv.Itoa(r.MarketsTraded), r.Volume.String(), strconv.Itoa(r.Trades),
+			strconv.Itoa(r.OpenPositions), strconv.Itoa(r.ClosedPositions),
+		})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
+
+// WriteTable writes the ranked rows as an aligned text table
+func (l *Leaderboard) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "#\tChange\tWallet\tScore\tΔ Score\tTotal PnL\tValue\tMarkets\tVolume\t")
+	for _, r := range l.Rows {
+		change, scoreChange := "new", ""
+		if !r.New {
+			switch {
+			case r.RankChange > 0:
+				change = "+" + strconv.Itoa(r.RankChange)
+			case r.RankChange < 0:
+				change = strconv.Itoa(r.RankChange)
+			default:
+				change = "="
+			}
+			scoreChange = r.ScoreChange.StringFixed(2)
+		}
+		wallet := r.Name
+		if wallet == "" {
+			wallet = r.User
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t\n",
+			r.Rank, change, wallet, r.Score.StringFixed(2), scoreChange,
+			r.TotalPnl.StringFixed(2), r.Value.StringFixed(2), r.MarketsTraded, r.Volume.StringFixed(2))
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write table: %w", err)
+	}
+	return nil
+}
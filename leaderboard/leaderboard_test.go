@@ -0,0 +1,198 @@
+package leaderboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+// fakeWallets serves per-wallet data and counts requests
+type fakeWallets struct {
+	mu       sync.Mutex
+	realized map[string]string
+	requests int
+}
+
+func (f *fakeWallets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+
+	user := r.URL.Query().Get("user")
+	if user == "0xbad" {
+		http.Error(w, `{"error":"internal error"}`, http.StatusInternalServerError)
+		return
+	}
+	switch r.URL.Path {
+	case "/closed-positions":
+		fmt.Fprintf(w, `[{"proxyWallet":%q,"realizedPnl":%q}]`, user, f.realized[user])
+	case "/positions":
+		fmt.Fprintf(w, `[{"proxyWallet":%q,"realizedPnl":"1","cashPnl":"2"}]`, user)
+	case "/value":
+		fmt.Fprintf(w, `[{"user":%q,"value":"100"}]`, user)
+	case "/traded":
+		fmt.Fprintf(w, `{"user":%q,"traded":7}`, user)
+	case "/activity":
+		if r.URL.Query().Get("type") != "TRADE" || r.URL.Query().Get("start") == "" {
+			http.Error(w, `{"error":"unexpected query"}`, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `[{"proxyWallet":%q,"type":"TRADE","timestamp":%d,"usdcSize":"25"}]`, user, time.Unix(1_000_000, 0).Unix())
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestBuilder(t *testing.T, api *fakeWallets, opts *Options) *Builder {
+	t.Helper()
+	client, err := polymarketdata.NewClient(&http.Client{Transport: handlerTransport{api}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewBuilder(client, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestBuild(t *testing.T) {
+	api := &fakeWallets{realized: map[string]string{"0xa": "10", "0xb": "50", "0xc": "30"}}
+	b := newTestBuilder(t, api, &Options{Concurrency: 2})
+	now := time.Unix(1_000_100, 0)
+	b.now = func() time.Time { return now }
+
+	board, err := b.Build(context.Background(), []string{"0xa", "0xb", "0xc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(board.Rows) != 3 || len(board.Errors) != 0 {
+		t.Fatalf("expected 3 rows, got %d (%v)", len(board.Rows), board.Errors)
+	}
+
+	top := board.Rows[0]
+	if top.User != "0xb" || top.Rank != 1 || !top.New {
+		t.Errorf("unexpected top row %+v", top)
+	}
+	// Realized 50 + 1 from the open position, unrealized 2
+	if !top.TotalPnl.Equal(decimal.NewFromInt(53)) || !top.RealizedPnl.Equal(decimal.NewFromInt(51)) {
+		t.Errorf("unexpected pnl %s / %s", top.TotalPnl, top.RealizedPnl)
+	}
+	if !top.Value.Equal(decimal.NewFromInt(100)) || top.MarketsTraded != 7 || !top.Volume.Equal(decimal.NewFromInt(25)) || top.Trades != 1 {
+		t.Errorf("unexpected figures %+v", top.Entry)
+	}
+
+	// Within the TTL nothing is fetched again
+	requests := api.requests
+	if _, err := b.Build(context.Background(), []string{"0xa", "0xb", "0xc"}); err != nil {
+		t.Fatal(err)
+	}
+	if api.requests != requests {
+		t.Errorf("expected cached data to be reused, got %d new requests", api.requests-requests)
+	}
+
+	// After the TTL, fresh data moves 0xa to the top
+	api.realized["0xa"] = "100"
+	now = now.Add(DefaultCacheTTL)
+	board, err = b.Build(context.Background(), []string{"0xa", "0xb", "0xc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := board.Rows[0]
+	if first.User != "0xa" || first.New || first.PreviousRank != 3 || first.RankChange != 2 || !first.ScoreChange.Equal(decimal.NewFromInt(90)) {
+		t.Errorf("unexpected deltas %+v", first)
+	}
+	if board.Rows[1].RankChange != -1 {
+		t.Errorf("expected 0xb to drop one place, got %+v", board.Rows[1])
+	}
+}
+
+func TestBuildCustomScoreAndErrors(t *testing.T) {
+	api := &fakeWallets{realized: map[string]string{"0xa": "10", "0xb": "-5"}}
+	byLoss := func(e Entry) decimal.Decimal { return e.RealizedPnl.Neg() }
+	b := newTestBuilder(t, api, &Options{Score: byLoss, CacheTTL: -1})
+
+	board, err := b.Build(context.Background(), []string{"0xa", "0xb", "0xbad"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(board.Rows) != 2 || board.Rows[0].User != "0xb" {
+		t.Errorf("expected the custom score to rank 0xb first, got %+v", board.Rows)
+	}
+	if board.Errors["0xbad"] == nil {
+		t.Error("expected the failing wallet to be reported")
+	}
+}
+
+func TestRankTies(t *testing.T) {
+	rows := Rank([]Entry{
+		{User: "0xc", TotalPnl: decimal.NewFromInt(5)},
+		{User: "0xB", TotalPnl: decimal.NewFromInt(9)},
+		{User: "0xa", TotalPnl: decimal.NewFromInt(9)},
+	}, nil)
+	want := []struct {
+		user string
+		rank int
+	}{{"0xa", 1}, {"0xB", 1}, {"0xc", 3}}
+	for i, w := range want {
+		if rows[i].User != w.user || rows[i].Rank != w.rank {
+			t.Errorf("row %d: got %s rank %d, want %s rank %d", i, rows[i].User, rows[i].Rank, w.user, w.rank)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	previous := &Leaderboard{Rows: Rank([]Entry{{User: "0xa", TotalPnl: decimal.NewFromInt(1)}}, nil)}
+	board := &Leaderboard{Rows: Rank([]Entry{
+		{User: "0xa", Name: "alice", TotalPnl: decimal.NewFromInt(3)},
+		{User: "0xb", TotalPnl: decimal.NewFromInt(5)},
+	}, nil)}
+	ApplyDeltas(board, previous)
+
+	var buf bytes.Buffer
+	if err := board.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || len(records[0]) != len(csvHeader) {
+		t.Fatalf("unexpected csv %v", records)
+	}
+	if records[1][3] != "0xb" || records[1][1] != "" {
+		t.Errorf("expected a new wallet without previous rank, got %v", records[1])
+	}
+	if records[2][1] != "1" || records[2][2] != "-1" || records[2][6] != "2" {
+		t.Errorf("unexpected deltas %v", records[2])
+	}
+
+	buf.Reset()
+	if err := board.WriteTable(&buf); err != nil {
+		t.Fatal(err)
+	}
+	table := buf.String()
+	if !strings.Contains(table, "alice") || !strings.Contains(table, "new") || !strings.Contains(table, "-1") {
+		t.Errorf("unexpected table:\n%s", table)
+	}
+}
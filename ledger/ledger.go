@@ -0,0 +1,441 @@
+// Package ledger reconstructs cost basis from activity history.
+//
+// A Ledger replays Activity records in timestamp order and keeps tax lots per
+// outcome token, matching disposals against lots with the FIFO, LIFO or
+// average-cost method. Amounts are decimal-exact except where a partially
+// consumed lot has to be prorated, which rounds to decimal.DivisionPrecision.
+package ledger
+
+import (
+	"fmt"
+	"sort"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// Method selects how disposals are matched against lots
+type Method string
+
+const (
+	MethodFIFO    Method = "FIFO"    // Dispose of the oldest lots first
+	MethodLIFO    Method = "LIFO"    // Dispose of the newest lots first
+	MethodAverage Method = "AVERAGE" // Pool all lots at their average cost
+)
+
+// Key identifies an outcome token by market and outcome index. Splits, merges
+// and redemptions do not always carry a token ID, so lots are keyed this way.
+type Key struct {
+	ConditionId  string
+	OutcomeIndex int
+}
+
+// Lot is an open acquisition of outcome tokens
+type Lot struct {
+	Key
+	Asset           string                      // Token ID, if known
+	AcquiredAt      int64                       // Unix seconds. For the average method, the earliest open acquisition
+	Size            decimal.Decimal             // Tokens still held
+	Cost            decimal.Decimal             // Cost of the tokens still held
+	Source          polymarketdata.ActivityType // TRADE or SPLIT
+	TransactionHash string
+}
+
+// Price returns the cost per token of the lot
+func (l Lot) Price() decimal.Decimal {
+	if l.Size.IsZero() {
+		return decimal.Zero
+	}
+	return l.Cost.Div(l.Size)
+}
+
+// Disposal is a sale, merge, redemption or conversion matched against a single lot
+type Disposal struct {
+	Key
+	Asset           string
+	Type            polymarketdata.ActivityType // TRADE, MERGE, REDEEM or CONVERSION
+	TransactionHash string
+	AcquiredAt      int64           // Acquisition time of the matched lot; 0 if unmatched
+	DisposedAt      int64           // Unix seconds
+	Size            decimal.Decimal // Tokens disposed of
+	Price           decimal.Decimal // Proceeds per token (the settlement price for redemptions)
+	Proceeds        decimal.Decimal
+	CostBasis       decimal.Decimal
+	Gain            decimal.Decimal // Proceeds - CostBasis
+	Unmatched       bool            // More tokens were disposed of than the history shows were acquired
+}
+
+// Income is a reward payment
+type Income struct {
+	ConditionId     string
+	Asset           string
+	Timestamp       int64
+	Amount          decimal.Decimal
+	TransactionHash string
+}
+
+// Holding is the reconstructed position in a single outcome token
+type Holding struct {
+	Key
+	Asset       string
+	Size        decimal.Decimal // Tokens held
+	CostBasis   decimal.Decimal // Cost of the tokens held
+	AvgPrice    decimal.Decimal // CostBasis / Size
+	Acquired    decimal.Decimal // Total tokens ever acquired
+	Proceeds    decimal.Decimal // Total proceeds of disposals
+	RealizedPnl decimal.Decimal // Total gain of disposals
+}
+
+// Ledger replays activity and maintains lots per outcome token
+type Ledger struct {
+	method    Method
+	positions map[Key]*position
+	order     []Key
+	disposals []Disposal
+	income    []Income
+	last      int64
+}
+
+type position struct {
+	key      Key
+	asset    string
+	lots     []Lot
+	acquired decimal.Decimal
+	proceeds decimal.Decimal
+	realized decimal.Decimal
+}
+
+// New creates an empty ledger using the given matching method
+func New(method Method) (*Ledger, error) {
+	switch method {
+	case MethodFIFO, MethodLIFO, MethodAverage:
+	default:
+		return nil, fmt.Errorf("unknown cost basis method %q", method)
+	}
+	return &Ledger{
+		method:    method,
+		positions: make(map[Key]*position),
+	}, nil
+}
+
+// Method returns the ledger's matching method
+func (l *Ledger) Method() Method {
+	return l.method
+}
+
+// Replay sorts activities by timestamp and applies them. Within the same
+// timestamp, acquisitions are applied before disposals.
+func (l *Ledger) Replay(activities []polymarketdata.Activity) error {
+	sorted := make([]polymarketdata.Activity, len(activities))
+	copy(sorted, activities)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Timestamp != sorted[j].Timestamp {
+			return sorted[i].Timestamp < sorted[j].Timestamp
+		}
+		return isAcquisition(sorted[i]) && !isAcquisition(sorted[j])
+	})
+	for _, a := range sorted {
+		if err := l.Apply(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply applies a single activity record. Records must be applied in timestamp order.
+//
+//   - TRADE buys open a lot; sells dispose of tokens for UsdcSize.
+//   - SPLIT opens a lot in both outcomes, each at half the USDC paid.
+//   - MERGE disposes of Size tokens of both outcomes, each for half the USDC received.
+//   - REDEEM disposes of every open lot in the market. If exactly one outcome's
+//     holding equals the payout, it settles at 1 and the others at 0; otherwise
+//     the payout is spread evenly over all tokens.
+//   - CONVERSION disposes of Size tokens of the record's outcome for UsdcSize.
+//   - REWARD is recorded as income.
+func (l *Ledger) Apply(a polymarketdata.Activity) error {
+	if a.Timestamp < l.last {
+		return fmt.Errorf("activity %s at %d is older than the previous record at %d", a.TransactionHash, a.Timestamp, l.last)
+	}
+	l.last = a.Timestamp
+
+	key := Key{ConditionId: a.ConditionId, OutcomeIndex: a.OutcomeIndex}
+	switch a.Type {
+	case polymarketdata.ActivityTypeTrade:
+		switch a.Side {
+		case polymarketdata.TradeSideBuy:
+			l.acquire(key, a, a.Size, cashAmount(a))
+		case polymarketdata.TradeSideSell:
+			l.dispose(key, a, a.Size, cashAmount(a))
+		default:
+			return fmt.Errorf("trade %s has unknown side %q", a.TransactionHash, a.Side)
+		}
+
+	case polymarketdata.ActivityTypeSplit:
+		half := a.UsdcSize.Div(decimal.NewFromInt(2))
+		l.acquire(Key{a.ConditionId, 0}, a, a.Size, half)
+		l.acquire(Key{a.ConditionId, 1}, a, a.Size, a.UsdcSize.Sub(half))
+
+	case polymarketdata.ActivityTypeMerge:
+		half := a.UsdcSize.Div(decimal.NewFromInt(2))
+		l.dispose(Key{a.ConditionId, 0}, a, a.Size, half)
+		l.dispose(Key{a.ConditionId, 1}, a, a.Size, a.UsdcSize.Sub(half))
+
+	case polymarketdata.ActivityTypeRedeem:
+		l.redeem(a)
+
+	case polymarketdata.ActivityTypeConversion:
+		l.dispose(key, a, a.Size, a.UsdcSize)
+
+	case polymarketdata.ActivityTypeReward:
+		l.income = append(l.income, Income{
+			ConditionId:     a.ConditionId,
+			Asset:           a.Asset,
+			Timestamp:       a.Timestamp,
+			Amount:          a.UsdcSize,
+			TransactionHash: a.TransactionHash,
+		})
+
+	default:
+		return fmt.Errorf("activity %s has unknown type %q", a.TransactionHash, a.Type)
+	}
+	return nil
+}
+
+// Holdings returns the reconstructed position per outcome token, in first-seen order
+func (l *Ledger) Holdings() []Holding {
+	holdings := make([]Holding, 0, len(l.order))
+	for _, key := range l.order {
+		holdings = append(holdings, l.positions[key].holding())
+	}
+	return holdings
+}
+
+// Holding returns the reconstructed position for a single outcome token
+func (l *Ledger) Holding(key Key) (Holding, bool) {
+	p, ok := l.positions[key]
+	if !ok {
+		return Holding{}, false
+	}
+	return p.holding(), true
+}
+
+// OpenLots returns all open lots, grouped by outcome token in first-seen order
+func (l *Ledger) OpenLots() []Lot {
+	var lots []Lot
+	for _, key := range l.order {
+		for _, lot := range l.positions[key].lots {
+			lot.Asset = l.positions[key].asset
+			lots = append(lots, lot)
+		}
+	}
+	return lots
+}
+
+// Disposals returns every disposal in the order it happened
+func (l *Ledger) Disposals() []Disposal {
+	out := make([]Disposal, len(l.disposals))
+	copy(out, l.disposals)
+	return out
+}
+
+// Income returns every reward payment in the order it happened
+func (l *Ledger) Income() []Income {
+	out := make([]Income, len(l.income))
+	copy(out, l.income)
+	return out
+}
+
+func isAcquisition(a polymarketdata.Activity) bool {
+	return a.Type == polymarketdata.ActivityTypeSplit ||
+		(a.Type == polymarketdata.ActivityTypeTrade && a.Side == polymarketdata.TradeSideBuy)
+}
+
+// cashAmount returns the USDC value of a trade, falling back to Size × Price
+func cashAmount(a polymarketdata.Activity) decimal.Decimal {
+	if !a.UsdcSize.IsZero() {
+		return a.UsdcSize
+	}
+	return a.Size.Mul(a.Price)
+}
+
+func (p *position) holding() Holding {
+	h := Holding{
+		Key:         p.key,
+		Asset:       p.asset,
+		Acquired:    p.acquired,
+		Proceeds:    p.proceeds,
+		RealizedPnl: p.realized,
+	}
+	for _, lot := range p.lots {
+		h.Size = h.Size.Add(lot.Size)
+		h.CostBasis = h.CostBasis.Add(lot.Cost)
+	}
+	if !h.Size.IsZero() {
+		h.AvgPrice = h.CostBasis.Div(h.Size)
+	}
+	return h
+}
+
+func (l *Ledger) position(key Key, asset string) *position {
+	p, ok := l.positions[key]
+	if !ok {
+		p = &position{key: key}
+		l.positions[key] = p
+		l.order = append(l.order, key)
+	}
+	if asset != "" && p.asset == "" {
+		p.asset = asset
+	}
+	return p
+}
+
+// assetFor returns the record's token ID if it refers to the given outcome
+func assetFor(a polymarketdata.Activity, key Key) string {
+	if a.OutcomeIndex == key.OutcomeIndex {
+		return a.Asset
+	}
+	return ""
+}
+
+func (l *Ledger) acquire(key Key, a polymarketdata.Activity, size, cost decimal.Decimal) {
+	if !size.IsPositive() {
+		return
+	}
+	p := l.position(key, assetFor(a, key))
+	p.acquired = p.acquired.Add(size)
+
+	lot := Lot{
+		Key:             key,
+		AcquiredAt:      a.Timestamp,
+		Size:            size,
+		Cost:            cost,
+		Source:          a.Type,
+		TransactionHash: a.TransactionHash,
+	}
+	if l.method == MethodAverage && len(p.lots) > 0 {
+		pool := &p.lots[0]
+		pool.Size = pool.Size.Add(size)
+		pool.Cost = pool.Cost.Add(cost)
+		return
+	}
+	p.lots = append(p.lots, lot)
+}
+
+// dispose matches size tokens against open lots and records the disposals
+func (l *Ledger) dispose(key Key, a polymarketdata.Activity, size, proceeds decimal.Decimal) {
+	if !size.IsPositive() {
+		return
+	}
+	p := l.position(key, assetFor(a, key))
+	p.proceeds = p.proceeds.Add(proceeds)
+
+	remaining := size
+	remainingProceeds := proceeds
+	for remaining.IsPositive() && len(p.lots) > 0 {
+		i := 0
+		if l.method == MethodLIFO {
+			i = len(p.lots) - 1
+		}
+		lot := &p.lots[i]
+
+		take := decimal.Min(remaining, lot.Size)
+		cost := lot.Cost
+		if take.LessThan(lot.Size) {
+			cost = lot.Cost.Mul(take).Div(lot.Size)
+		}
+		chunkProceeds := remainingProceeds
+		if take.LessThan(remaining) {
+			chunkProceeds = remainingProceeds.Mul(take).Div(remaining)
+		}
+
+		l.record(p, a, lot.AcquiredAt, take, chunkProceeds, cost, false)
+
+		lot.Size = lot.Size.Sub(take)
+		lot.Cost = lot.Cost.Sub(cost)
+		remaining = remaining.Sub(take)
+		remainingProceeds = remainingProceeds.Sub(chunkProceeds)
+		if lot.Size.IsZero() {
+			p.lots = append(p.lots[:i], p.lots[i+1:]...)
+		}
+	}
+
+	if remaining.IsPositive() {
+		l.record(p, a, 0, remaining, remainingProceeds, decimal.Zero, true)
+	}
+}
+
+func (l *Ledger) record(p *position, a polymarketdata.Activity, acquiredAt int64, size, proceeds, cost decimal.Decimal, unmatched bool) {
+	gain := proceeds.Sub(cost)
+	p.realized = p.realized.Add(gain)
+	l.disposals = append(l.disposals, Disposal{
+		Key:             p.key,
+		Asset:           p.asset,
+		Type:            a.Type,
+		TransactionHash: a.TransactionHash,
+		AcquiredAt:      acquiredAt,
+		DisposedAt:      a.Timestamp,
+		Size:            size,
+		Price:           proceeds.Div(size),
+		Proceeds:        proceeds,
+		CostBasis:       cost,
+		Gain:            gain,
+		Unmatched:       unmatched,
+	})
+}
+
+// redeem settles every open lot of the record's market
+func (l *Ledger) redeem(a polymarketdata.Activity) {
+	var keys []Key
+	sizes := make(map[Key]decimal.Decimal)
+	total := decimal.Zero
+	for _, key := range l.order {
+		if key.ConditionId != a.ConditionId {
+			continue
+		}
+		size := decimal.Zero
+		for _, lot := range l.positions[key].lots {
+			size = size.Add(lot.Size)
+		}
+		if size.IsPositive() {
+			keys = append(keys, key)
+			sizes[key] = size
+			total = total.Add(size)
+		}
+	}
+
+	if len(keys) == 0 {
+		// Nothing known to redeem: record the payout against the record's outcome
+		if a.Size.IsPositive() || a.UsdcSize.IsPositive() {
+			size := a.Size
+			if !size.IsPositive() {
+				size = a.UsdcSize
+			}
+			l.dispose(Key{a.ConditionId, a.OutcomeIndex}, a, size, a.UsdcSize)
+		}
+		return
+	}
+
+	var winners []Key
+	for _, key := range keys {
+		if sizes[key].Equal(a.UsdcSize) {
+			winners = append(winners, key)
+		}
+	}
+
+	remaining := a.UsdcSize
+	for i, key := range keys {
+		var proceeds decimal.Decimal
+		switch {
+		case len(winners) == 1 && key == winners[0]:
+			proceeds = a.UsdcSize
+		case len(winners) == 1:
+			proceeds = decimal.Zero
+		case i == len(keys)-1:
+			proceeds = remaining
+		default:
+			proceeds = a.UsdcSize.Mul(sizes[key]).Div(total)
+		}
+		remaining = remaining.Sub(proceeds)
+		l.dispose(key, a, sizes[key], proceeds)
+	}
+}
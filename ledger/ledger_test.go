@@ -0,0 +1,173 @@
+package ledger
+
+import (
+	"testing"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func trade(ts int64, side polymarketdata.TradeSide, outcome int, size, price string) polymarketdata.Activity {
+	return polymarketdata.Activity{
+		Timestamp:    ts,
+		Type:         polymarketdata.ActivityTypeTrade,
+		Side:         side,
+		ConditionId:  "0xc",
+		Asset:        []string{"yes", "no"}[outcome],
+		OutcomeIndex: outcome,
+		Size:         d(size),
+		Price:        d(price),
+		UsdcSize:     d(size).Mul(d(price)),
+	}
+}
+
+func replay(t *testing.T, method Method, activities ...polymarketdata.Activity) *Ledger {
+	t.Helper()
+	l, err := New(method)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := l.Replay(activities); err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	return l
+}
+
+func assertDecimal(t *testing.T, name string, got, want decimal.Decimal) {
+	t.Helper()
+	if !got.Equal(want) {
+		t.Errorf("%s = %s, want %s", name, got, want)
+	}
+}
+
+func TestMethods(t *testing.T) {
+	history := []polymarketdata.Activity{
+		trade(3, polymarketdata.TradeSideSell, 0, "10", "0.50"),
+		trade(1, polymarketdata.TradeSideBuy, 0, "10", "0.20"),
+		trade(2, polymarketdata.TradeSideBuy, 0, "10", "0.40"),
+	}
+
+	cases := []struct {
+		method    Method
+		gain      string
+		remaining string
+	}{
+		{MethodFIFO, "3", "4"},
+		{MethodLIFO, "1", "2"},
+		{MethodAverage, "2", "3"},
+	}
+	for _, tc := range cases {
+		t.Run(string(tc.method), func(t *testing.T) {
+			l := replay(t, tc.method, history...)
+			h, ok := l.Holding(Key{"0xc", 0})
+			if !ok {
+				t.Fatal("holding not found")
+			}
+			assertDecimal(t, "realized", h.RealizedPnl, d(tc.gain))
+			assertDecimal(t, "remaining cost", h.CostBasis, d(tc.remaining))
+			assertDecimal(t, "size", h.Size, d("10"))
+			if h.Asset != "yes" {
+				t.Errorf("asset = %q", h.Asset)
+			}
+		})
+	}
+}
+
+func TestDisposalSpansLots(t *testing.T) {
+	l := replay(t, MethodFIFO,
+		trade(1, polymarketdata.TradeSideBuy, 0, "3", "0.1"),
+		trade(2, polymarketdata.TradeSideBuy, 0, "3", "0.3"),
+		trade(3, polymarketdata.TradeSideSell, 0, "4", "0.5"),
+	)
+	disposals := l.Disposals()
+	if len(disposals) != 2 {
+		t.Fatalf("expected 2 disposals, got %d", len(disposals))
+	}
+	if disposals[0].AcquiredAt != 1 || disposals[1].AcquiredAt != 2 {
+		t.Errorf("acquired dates = %d, %d", disposals[0].AcquiredAt, disposals[1].AcquiredAt)
+	}
+	assertDecimal(t, "first proceeds", disposals[0].Proceeds, d("1.5"))
+	assertDecimal(t, "second proceeds", disposals[1].Proceeds, d("0.5"))
+	assertDecimal(t, "second cost", disposals[1].CostBasis, d("0.3"))
+
+	lots := l.OpenLots()
+	if len(lots) != 1 || !lots[0].Size.Equal(d("2")) || !lots[0].Price().Equal(d("0.3")) {
+		t.Errorf("open lots = %+v", lots)
+	}
+}
+
+func TestSplitMergeRedeemReward(t *testing.T) {
+	l := replay(t, MethodFIFO,
+		polymarketdata.Activity{Timestamp: 1, Type: polymarketdata.ActivityTypeSplit, ConditionId: "0xc", Size: d("100"), UsdcSize: d("100")},
+		polymarketdata.Activity{Timestamp: 2, Type: polymarketdata.ActivityTypeMerge, ConditionId: "0xc", Size: d("40"), UsdcSize: d("40")},
+		trade(3, polymarketdata.TradeSideSell, 1, "60", "0.25"),
+		polymarketdata.Activity{Timestamp: 4, Type: polymarketdata.ActivityTypeRedeem, ConditionId: "0xc", Size: d("60"), UsdcSize: d("60")},
+		polymarketdata.Activity{Timestamp: 5, Type: polymarketdata.ActivityTypeReward, ConditionId: "0xc", UsdcSize: d("1.25")},
+	)
+
+	yes, _ := l.Holding(Key{"0xc", 0})
+	no, _ := l.Holding(Key{"0xc", 1})
+	if !yes.Size.IsZero() || !no.Size.IsZero() {
+		t.Errorf("expected everything disposed, got yes=%s no=%s", yes.Size, no.Size)
+	}
+	// YES: split at 0.5, merged 40 at 0.5, redeemed 60 at 1
+	assertDecimal(t, "yes realized", yes.RealizedPnl, d("30"))
+	// NO: split at 0.5, merged 40 at 0.5, sold 60 at 0.25
+	assertDecimal(t, "no realized", no.RealizedPnl, d("-15"))
+	if no.Asset != "no" {
+		t.Errorf("asset learned from trade = %q", no.Asset)
+	}
+
+	var redeemed []Disposal
+	for _, disp := range l.Disposals() {
+		if disp.Type == polymarketdata.ActivityTypeRedeem {
+			redeemed = append(redeemed, disp)
+		}
+	}
+	if len(redeemed) != 1 || !redeemed[0].Price.Equal(d("1")) {
+		t.Errorf("redeem disposals = %+v", redeemed)
+	}
+
+	income := l.Income()
+	if len(income) != 1 || !income[0].Amount.Equal(d("1.25")) {
+		t.Errorf("income = %+v", income)
+	}
+}
+
+func TestRedeemLoserAndUnmatched(t *testing.T) {
+	l := replay(t, MethodFIFO,
+		trade(1, polymarketdata.TradeSideBuy, 0, "10", "0.6"),
+		trade(2, polymarketdata.TradeSideBuy, 1, "5", "0.4"),
+		polymarketdata.Activity{Timestamp: 3, Type: polymarketdata.ActivityTypeRedeem, ConditionId: "0xc", UsdcSize: d("5")},
+		trade(4, polymarketdata.TradeSideSell, 0, "2", "0.9"),
+	)
+	yes, _ := l.Holding(Key{"0xc", 0})
+	no, _ := l.Holding(Key{"0xc", 1})
+	assertDecimal(t, "loser realized", yes.RealizedPnl, d("-6").Add(d("1.8")))
+	assertDecimal(t, "winner realized", no.RealizedPnl, d("3"))
+
+	last := l.Disposals()[len(l.Disposals())-1]
+	if !last.Unmatched || !last.CostBasis.IsZero() {
+		t.Errorf("sale without holdings should be unmatched: %+v", last)
+	}
+}
+
+func TestApplyRejectsOutOfOrderAndUnknown(t *testing.T) {
+	l, _ := New(MethodFIFO)
+	if err := l.Apply(trade(5, polymarketdata.TradeSideBuy, 0, "1", "0.5")); err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if err := l.Apply(trade(4, polymarketdata.TradeSideBuy, 0, "1", "0.5")); err == nil {
+		t.Error("expected out-of-order error")
+	}
+	if err := l.Apply(polymarketdata.Activity{Timestamp: 6, Type: "BOGUS"}); err == nil {
+		t.Error("expected unknown type error")
+	}
+	if _, err := New("HIFO"); err == nil {
+		t.Error("expected unknown method error")
+	}
+}
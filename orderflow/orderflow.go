@@ -0,0 +1,283 @@
+// Package orderflow measures buy/sell pressure and order-flow imbalance
+// per outcome token and per market.
+//
+// Flow is kept separate per outcome token. At the market level, buying
+// outcome 1 (usually NO) is pressure against outcome 0 (usually YES), the
+// same as selling outcome 0, so it is never counted as bullish for YES.
+package orderflow
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// precision is the number of decimal places kept by divisions
+const precision = 16
+
+// Strength grades how crowded one side of the flow is
+type Strength string
+
+const (
+	StrengthWeak     Strength = "WEAK"
+	StrengthModerate Strength = "MODERATE"
+	StrengthStrong   Strength = "STRONG"
+)
+
+// Thresholds are the pressure shares, between 0 and 1, above which one side is crowded
+type Thresholds struct {
+	Moderate decimal.Decimal
+	Strong   decimal.Decimal
+}
+
+// DefaultThresholds flag a side carrying more than 75% or 85% of the volume
+var DefaultThresholds = Thresholds{
+	Moderate: decimal.RequireFromString("0.75"),
+	Strong:   decimal.RequireFromString("0.85"),
+}
+
+// Options configures an analysis
+type Options struct {
+	Weight     polymarketdata.FilterType // Optional: Weigh trades by CASH (Size × Price) or TOKENS (Size). Default CASH
+	Window     time.Duration             // Optional: Only trades within this long before the newest trade. 0 means all trades
+	MinTrades  int                       // Optional: Flows with fewer trades never signal
+	Thresholds *Thresholds               // Optional: Default DefaultThresholds
+}
+
+// Signal is the outcome of classifying a flow against the thresholds
+type Signal struct {
+	Strength Strength
+	Side     polymarketdata.TradeSide // The crowded side, empty if the flow is balanced or has no volume
+	Pressure decimal.Decimal          // Share of the crowded side, between 0 and 1
+}
+
+// Flow is the order flow of a single outcome token
+type Flow struct {
+	Asset        string
+	ConditionId  string
+	OutcomeIndex int
+	Outcome      string
+	Trades       int
+	BuyTrades    int
+	SellTrades   int
+	BuyVolume    decimal.Decimal // Weighted
+	SellVolume   decimal.Decimal // Weighted
+	BuyPressure  decimal.Decimal // BuyVolume / (BuyVolume + SellVolume)
+	SellPressure decimal.Decimal
+	Imbalance    decimal.Decimal // (BuyVolume - SellVolume) / (BuyVolume + SellVolume), between -1 and 1
+	Signal       Signal
+}
+
+// MarketFlow is the directional flow of a binary market, seen from outcome 0.
+// Bullish flow buys outcome 0 or sells outcome 1; bearish flow does the opposite.
+// The signal's side is BUY when outcome 0 is crowded and SELL when outcome 1 is.
+type MarketFlow struct {
+	ConditionId     string
+	Title           string
+	Trades          int
+	Bullish         decimal.Decimal // Weighted
+	Bearish         decimal.Decimal // Weighted
+	BullishPressure decimal.Decimal // Bullish / (Bullish + Bearish)
+	BearishPressure decimal.Decimal
+	Imbalance       decimal.Decimal // (Bullish - Bearish) / (Bullish + Bearish), between -1 and 1
+	Outcomes        []Flow          // Per outcome token, by outcome index
+	Signal          Signal
+}
+
+// Report holds the flows of every asset and market in the analyzed trades
+type Report struct {
+	Start   time.Time
+	End     time.Time
+	Weight  polymarketdata.FilterType
+	Assets  []Flow       // Sorted by condition ID and outcome index
+	Markets []MarketFlow // Sorted by condition ID
+}
+
+// Analyze computes the order flow of trades
+func Analyze(trades []polymarketdata.Trade, opts *Options) (*Report, error) {
+	weight := polymarketdata.FilterTypeCash
+	thresholds := DefaultThresholds
+	var window time.Duration
+	var minTrades int
+	if opts != nil {
+		if opts.Weight != "" {
+			weight = opts.Weight
+		}
+		if opts.Thresholds != nil {
+			thresholds = *opts.Thresholds
+		}
+		window = opts.Window
+		minTrades = opts.MinTrades
+	}
+	if weight != polymarketdata.FilterTypeCash && weight != polymarketdata.FilterTypeTokens {
+		return nil, fmt.Errorf("invalid weight: %s", weight)
+	}
+	if err := thresholds.validate(); err != nil {
+		return nil, err
+	}
+
+	report := &Report{Weight: weight}
+	if len(trades) == 0 {
+		return report, nil
+	}
+
+	var newest int64
+	for _, t := range trades {
+		if t.Timestamp > newest {
+			newest = t.Timestamp
+		}
+	}
+	cutoff := int64(0)
+	if window > 0 {
+		cutoff = newest - int64(window/time.Second)
+	}
+
+	flows := make(map[string]*Flow)
+	markets := make(map[string]*MarketFlow)
+	oldest := newest
+	for _, t := range trades {
+		if window > 0 && t.Timestamp <= cutoff {
+			continue
+		}
+		if t.Side != polymarketdata.TradeSideBuy && t.Side != polymarketdata.TradeSideSell {
+			continue
+		}
+		if t.Timestamp < oldest {
+			oldest = t.Timestamp
+		}
+		w := t.Size
+		if weight == polymarketdata.FilterTypeCash {
+			w = t.Size.Mul(t.Price)
+		}
+
+		f, ok := flows[t.Asset]
+		if !ok {
+			f = &Flow{Asset: t.Asset, ConditionId: t.ConditionId, OutcomeIndex: t.OutcomeIndex, Outcome: t.Outcome}
+			flows[t.Asset] = f
+		}
+		m, ok := markets[t.ConditionId]
+		if !ok {
+			m = &MarketFlow{ConditionId: t.ConditionId}
+			markets[t.ConditionId] = m
+		}
+		if m.Title == "" {
+			m.Title = t.Title
+		}
+
+		f.Trades++
+		m.Trades++
+		bullish := t.OutcomeIndex == 0
+		switch t.Side {
+		case polymarketdata.TradeSideBuy:
+			f.BuyTrades++
+			f.BuyVolume = f.BuyVolume.Add(w)
+		case polymarketdata.TradeSideSell:
+			f.SellTrades++
+			f.SellVolume = f.SellVolume.Add(w)
+			bullish = !bullish
+		}
+		if bullish {
+			m.Bullish = m.Bullish.Add(w)
+		} else {
+			m.Bearish = m.Bearish.Add(w)
+		}
+	}
+
+	for _, f := range flows {
+		f.BuyPressure, f.SellPressure, f.Imbalance = pressure(f.BuyVolume, f.SellVolume)
+		if f.Trades >= minTrades {
+			f.Signal = thresholds.Classify(f.BuyPressure, f.SellPressure)
+		} else {
+			f.Signal = Signal{Strength: StrengthWeak}
+		}
+		report.Assets = append(report.Assets, *f)
+	}
+	sort.Slice(report.Assets, func(i, j int) bool {
+		a, b := report.Assets[i], report.Assets[j]
+		if a.ConditionId != b.ConditionId {
+			return a.ConditionId < b.ConditionId
+		}
+		if a.OutcomeIndex != b.OutcomeIndex {
+			return a.OutcomeIndex < b.OutcomeIndex
+		}
+		return a.Asset < b.Asset
+	})
+
+	for _, f := range report.Assets {
+		m := markets[f.ConditionId]
+		m.Outcomes = append(m.Outcomes, f)
+	}
+	for _, m := range markets {
+		m.BullishPressure, m.BearishPressure, m.Imbalance = pressure(m.Bullish, m.Bearish)
+		if m.Trades >= minTrades {
+			m.Signal = thresholds.Classify(m.BullishPressure, m.BearishPressure)
+		} else {
+			m.Signal = Signal{Strength: StrengthWeak}
+		}
+		report.Markets = append(report.Markets, *m)
+	}
+	sort.Slice(report.Markets, func(i, j int) bool {
+		return report.Markets[i].ConditionId < report.Markets[j].ConditionId
+	})
+
+	report.Start = time.Unix(oldest, 0)
+	report.End = time.Unix(newest, 0)
+	return report, nil
+}
+
+// Asset returns the flow of a single outcome token
+func (r *Report) Asset(asset string) (Flow, bool) {
+	for _, f := range r.Assets {
+		if f.Asset == asset {
+			return f, true
+		}
+	}
+	return Flow{}, false
+}
+
+// Market returns the flow of a single market
+func (r *Report) Market(conditionId string) (MarketFlow, bool) {
+	for _, m := range r.Markets {
+		if m.ConditionId == conditionId {
+			return m, true
+		}
+	}
+	return MarketFlow{}, false
+}
+
+// Classify grades buy and sell pressure shares. The crowded side is the one whose
+// share exceeds the moderate threshold; otherwise the signal is weak with no side.
+func (th Thresholds) Classify(buyPressure, sellPressure decimal.Decimal) Signal {
+	side, p := polymarketdata.TradeSideBuy, buyPressure
+	if sellPressure.GreaterThan(buyPressure) {
+		side, p = polymarketdata.TradeSideSell, sellPressure
+	}
+	switch {
+	case p.GreaterThan(th.Strong):
+		return Signal{Strength: StrengthStrong, Side: side, Pressure: p}
+	case p.GreaterThan(th.Moderate):
+		return Signal{Strength: StrengthModerate, Side: side, Pressure: p}
+	default:
+		return Signal{Strength: StrengthWeak, Pressure: p}
+	}
+}
+
+func (th Thresholds) validate() error {
+	half := decimal.NewFromFloat(0.5)
+	if th.Moderate.LessThan(half) || th.Strong.GreaterThan(decimal.NewFromInt(1)) || th.Strong.LessThan(th.Moderate) {
+		return fmt.Errorf("thresholds must satisfy 0.5 <= moderate <= strong <= 1, got %s and %s", th.Moderate, th.Strong)
+	}
+	return nil
+}
+
+// pressure returns the shares of buy and sell and the imbalance between them
+func pressure(buy, sell decimal.Decimal) (buyShare, sellShare, imbalance decimal.Decimal) {
+	total := buy.Add(sell)
+	if !total.IsPositive() {
+		return decimal.Zero, decimal.Zero, decimal.Zero
+	}
+	return buy.DivRound(total, precision), sell.DivRound(total, precision), buy.Sub(sell).DivRound(total, precision)
+}
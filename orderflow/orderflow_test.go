@@ -0,0 +1,174 @@
+package orderflow
+
+import (
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func trade(market string, outcomeIndex int, ts int64, side polymarketdata.TradeSide, size, price string) polymarketdata.Trade {
+	outcome := "Yes"
+	if outcomeIndex == 1 {
+		outcome = "No"
+	}
+	return polymarketdata.Trade{
+		Side:         side,
+		Asset:        market + "-" + outcome,
+		ConditionId:  market,
+		OutcomeIndex: outcomeIndex,
+		Size:         decimal.RequireFromString(size),
+		Price:        decimal.RequireFromString(price),
+		Timestamp:    ts,
+		MarketRef:    polymarketdata.MarketRef{Title: "Market " + market, Outcome: outcome},
+	}
+}
+
+// In m1 everyone buys, but most of the money buys NO. In m2 money leaves YES for NO.
+var fixture = []polymarketdata.Trade{
+	trade("m1", 0, 1000, polymarketdata.TradeSideBuy, "100", "0.5"),
+	trade("m1", 1, 1010, polymarketdata.TradeSideBuy, "200", "0.5"),
+	trade("m1", 0, 1020, polymarketdata.TradeSideSell, "10", "0.5"),
+	trade("m2", 1, 1030, polymarketdata.TradeSideBuy, "80", "0.2"),
+	trade("m2", 0, 1040, polymarketdata.TradeSideSell, "10", "0.8"),
+	trade("m2", 0, 1050, polymarketdata.TradeSideBuy, "1", "0.8"),
+}
+
+func expectDecimal(t *testing.T, name string, got decimal.Decimal, want string) {
+	t.Helper()
+	if !got.Round(4).Equal(decimal.RequireFromString(want)) {
+		t.Errorf("%s: got %s, want %s", name, got, want)
+	}
+}
+
+func TestAnalyzeAssets(t *testing.T) {
+	report, err := Analyze(fixture, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Weight != polymarketdata.FilterTypeCash {
+		t.Errorf("expected cash weighting by default, got %s", report.Weight)
+	}
+	if len(report.Assets) != 4 {
+		t.Fatalf("expected 4 assets, got %d", len(report.Assets))
+	}
+
+	yes, ok := report.Asset("m1-Yes")
+	if !ok {
+		t.Fatal("expected m1-Yes flow")
+	}
+	expectDecimal(t, "buy volume", yes.BuyVolume, "50")
+	expectDecimal(t, "sell volume", yes.SellVolume, "5")
+	expectDecimal(t, "buy pressure", yes.BuyPressure, "0.9091")
+	expectDecimal(t, "imbalance", yes.Imbalance, "0.8182")
+	if yes.Signal.Strength != StrengthStrong || yes.Signal.Side != polymarketdata.TradeSideBuy {
+		t.Errorf("unexpected signal %+v", yes.Signal)
+	}
+	if yes.BuyTrades != 1 || yes.SellTrades != 1 {
+		t.Errorf("unexpected trade counts %d/%d", yes.BuyTrades, yes.SellTrades)
+	}
+}
+
+func TestAnalyzeMarketsAreOutcomeAware(t *testing.T) {
+	report, err := Analyze(fixture, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Buying NO is bearish for YES, so m1 is balanced-to-bearish rather than 97% buys
+	m1, ok := report.Market("m1")
+	if !ok {
+		t.Fatal("expected m1 flow")
+	}
+	expectDecimal(t, "m1 bullish", m1.Bullish, "50")
+	expectDecimal(t, "m1 bearish", m1.Bearish, "105")
+	expectDecimal(t, "m1 bullish pressure", m1.BullishPressure, "0.3226")
+	if m1.Signal.Strength != StrengthWeak || m1.Signal.Side != "" {
+		t.Errorf("unexpected m1 signal %+v", m1.Signal)
+	}
+	if len(m1.Outcomes) != 2 || m1.Outcomes[0].OutcomeIndex != 0 || m1.Title != "Market m1" {
+		t.Errorf("unexpected m1 outcomes %+v", m1.Outcomes)
+	}
+
+	m2, _ := report.Market("m2")
+	expectDecimal(t, "m2 bearish pressure", m2.BearishPressure, "0.9677")
+	if m2.Signal.Strength != StrengthStrong || m2.Signal.Side != polymarketdata.TradeSideSell {
+		t.Errorf("unexpected m2 signal %+v", m2.Signal)
+	}
+}
+
+func TestAnalyzeTokenWeightAndThresholds(t *testing.T) {
+	report, err := Analyze(fixture, &Options{
+		Weight: polymarketdata.FilterTypeTokens,
+		Thresholds: &Thresholds{
+			Moderate: decimal.RequireFromString("0.6"),
+			Strong:   decimal.RequireFromString("0.7"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m1, _ := report.Market("m1")
+	expectDecimal(t, "m1 bullish", m1.Bullish, "100")
+	expectDecimal(t, "m1 bearish", m1.Bearish, "210")
+	if m1.Signal.Strength != StrengthModerate || m1.Signal.Side != polymarketdata.TradeSideSell {
+		t.Errorf("unexpected m1 signal %+v", m1.Signal)
+	}
+}
+
+func TestAnalyzeWindowAndMinTrades(t *testing.T) {
+	trades := append([]polymarketdata.Trade{
+		trade("m2", 0, 0, polymarketdata.TradeSideBuy, "1000", "0.8"),
+	}, fixture...)
+
+	report, err := Analyze(trades, &Options{Window: time.Minute, MinTrades: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Start.Unix() != 1000 || report.End.Unix() != 1050 {
+		t.Errorf("unexpected window %d - %d", report.Start.Unix(), report.End.Unix())
+	}
+
+	m2, _ := report.Market("m2")
+	if m2.Trades != 3 || m2.Signal.Strength != StrengthStrong {
+		t.Errorf("expected the old trade to be excluded, got %d trades and %+v", m2.Trades, m2.Signal)
+	}
+	yes, _ := report.Asset("m2-Yes")
+	if yes.Signal.Strength != StrengthWeak {
+		t.Errorf("expected no signal below MinTrades, got %+v", yes.Signal)
+	}
+}
+
+func TestAnalyzeSkipsUnknownSides(t *testing.T) {
+	trades := append([]polymarketdata.Trade{
+		trade("m1", 0, 1005, "", "100", "0.5"),
+		trade("m3", 0, 1005, "", "100", "0.5"),
+	}, fixture...)
+
+	report, err := Analyze(trades, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	yes, _ := report.Asset("m1-Yes")
+	if yes.Trades != 2 || yes.Trades != yes.BuyTrades+yes.SellTrades {
+		t.Errorf("expected the unknown side to be skipped, got %d trades (%d/%d)", yes.Trades, yes.BuyTrades, yes.SellTrades)
+	}
+	if m1, _ := report.Market("m1"); m1.Trades != 3 {
+		t.Errorf("expected 3 m1 trades, got %d", m1.Trades)
+	}
+	if _, ok := report.Market("m3"); ok {
+		t.Error("expected no flow for a market with only unknown sides")
+	}
+}
+
+func TestAnalyzeInvalidOptions(t *testing.T) {
+	if _, err := Analyze(fixture, &Options{Weight: "SHARES"}); err == nil {
+		t.Error("expected an error for an unknown weight")
+	}
+	th := &Thresholds{Moderate: decimal.RequireFromString("0.9"), Strong: decimal.RequireFromString("0.8")}
+	if _, err := Analyze(fixture, &Options{Thresholds: th}); err == nil {
+		t.Error("expected an error for inverted thresholds")
+	}
+}
@@ -0,0 +1,135 @@
+package polymarketdata
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// maxPageLimit is the largest page size accepted by the paged endpoints
+	maxPageLimit = 500
+	// maxPageOffset is the largest offset accepted by the paged endpoints
+	maxPageOffset = 10000
+)
+
+// GetAllPositions pages through GetPositions until every position is fetched.
+// Limit sets the page size (default 500); Offset is ignored.
+func (c *Client) GetAllPositions(ctx context.Context, params *GetPositionsParams) ([]Position, error) {
+	p := *params
+	if p.Limit == 0 {
+		p.Limit = maxPageLimit
+	}
+
+	var all []Position
+	for p.Offset = 0; ; p.Offset += p.Limit {
+		if p.Offset > maxPageOffset {
+			return nil, fmt.Errorf("positions exceed the maximum offset of %d", maxPageOffset)
+		}
+		page, err := c.GetPositions(ctx, &p)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < p.Limit {
+			return all, nil
+		}
+	}
+}
+
+// GetAllClosedPositions pages through GetClosedPositions until every closed position is fetched.
+// Limit sets the page size (default 500); Offset is ignored.
+func (c *Client) GetAllClosedPositions(ctx context.Context, params *GetClosedPositionsParams) ([]ClosedPosition, error) {
+	p := *params
+	if p.Limit == 0 {
+		p.Limit = maxPageLimit
+	}
+
+	var all []ClosedPosition
+	for p.Offset = 0; ; p.Offset += p.Limit {
+		if p.Offset > maxPageOffset {
+			return nil, fmt.Errorf("closed positions exceed the maximum offset of %d", maxPageOffset)
+		}
+		page, err := c.GetClosedPositions(ctx, &p)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < p.Limit {
+			return all, nil
+		}
+	}
+}
+
+// GetAllActivity pages through GetActivity in ascending timestamp order until
+// every record from Start to End is fetched. Limit sets the page size
+// (default 500); Offset, SortBy and SortDirection are ignored.
+func (c *Client) GetAllActivity(ctx context.Context, params *GetActivityParams) ([]Activity, error) {
+	var all []Activity
+	err := c.pageActivity(ctx, params, func(page []Activity) error {
+		all = append(all, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// pageActivity calls fn with each page of activity in ascending timestamp order.
+// When the offset limit is reached it restarts from the last seen timestamp,
+// dropping records at that timestamp that were already delivered.
+func (c *Client) pageActivity(ctx context.Context, params *GetActivityParams, fn func([]Activity) error) error {
+	p := *params
+	if p.Limit == 0 {
+		p.Limit = maxPageLimit
+	}
+	p.SortBy = ActivitySortByTimestamp
+	p.SortDirection = SortDirectionAsc
+	p.Offset = 0
+
+	// Keys of delivered records at the newest timestamp, to skip after a restart
+	var boundaryTs int64
+	boundary := make(map[string]bool)
+
+	for {
+		page, err := c.GetActivity(ctx, &p)
+		if err != nil {
+			return err
+		}
+
+		fresh := page[:0:0]
+		for _, a := range page {
+			key := ActivityKey(a)
+			if a.Timestamp == boundaryTs && boundary[key] {
+				continue
+			}
+			if a.Timestamp != boundaryTs {
+				boundaryTs = a.Timestamp
+				boundary = make(map[string]bool)
+			}
+			boundary[key] = true
+			fresh = append(fresh, a)
+		}
+		if len(fresh) > 0 {
+			if err := fn(fresh); err != nil {
+				return err
+			}
+		}
+
+		if len(page) < p.Limit {
+			return nil
+		}
+		if p.Offset+p.Limit <= maxPageOffset {
+			p.Offset += p.Limit
+			continue
+		}
+
+		// Offset limit reached: continue from the newest timestamp seen
+		last := page[len(page)-1].Timestamp
+		if last <= p.Start {
+			return fmt.Errorf("more than %d activity records share timestamp %d", maxPageOffset, last)
+		}
+		p.Start = last
+		p.Offset = 0
+	}
+}
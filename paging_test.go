@@ -0,0 +1,104 @@
+package polymarketdata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// fakeActivityAPI serves /activity like the real API: filtered by start,
+// sorted ascending and paged by limit and offset
+type fakeActivityAPI struct {
+	activity []Activity // sorted ascending by timestamp
+	requests int
+}
+
+func (f *fakeActivityAPI) serveActivity(w http.ResponseWriter, r *http.Request) {
+	f.requests++
+	q := r.URL.Query()
+	if q.Get("sortDirection") != "ASC" || q.Get("sortBy") != "TIMESTAMP" {
+		http.Error(w, `{"error":"expected ascending timestamp order"}`, http.StatusBadRequest)
+		return
+	}
+	start, _ := strconv.ParseInt(q.Get("start"), 10, 64)
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if offset > 10000 {
+		http.Error(w, `{"error":"offset too large"}`, http.StatusBadRequest)
+		return
+	}
+
+	var matched []Activity
+	for _, a := range f.activity {
+		if a.Timestamp >= start {
+			matched = append(matched, a)
+		}
+	}
+	page := []Activity{}
+	if offset < len(matched) {
+		end := min(offset+limit, len(matched))
+		page = matched[offset:end]
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+func generateActivity(n int) []Activity {
+	activity := make([]Activity, n)
+	for i := range activity {
+		activity[i] = Activity{
+			ProxyWallet:     "0x1",
+			Type:            ActivityTypeTrade,
+			Timestamp:       int64(1000 + i/3),
+			TransactionHash: "0x" + strconv.Itoa(i),
+			Size:            decimal.NewFromInt(int64(i)),
+		}
+	}
+	return activity
+}
+
+func TestGetAllActivityPastOffsetLimit(t *testing.T) {
+	api := &fakeActivityAPI{activity: generateActivity(10600)}
+	client := newTestClient(t, http.HandlerFunc(api.serveActivity))
+
+	all, err := client.GetAllActivity(context.Background(), &GetActivityParams{User: "0x1"})
+	if err != nil {
+		t.Fatalf("GetAllActivity failed: %v", err)
+	}
+	if len(all) != len(api.activity) {
+		t.Fatalf("got %d records, want %d", len(all), len(api.activity))
+	}
+	seen := make(map[string]bool)
+	for _, a := range all {
+		if seen[a.TransactionHash] {
+			t.Fatalf("duplicate record %s", a.TransactionHash)
+		}
+		seen[a.TransactionHash] = true
+	}
+}
+
+func TestGetAllPositionsPages(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		n := 2
+		if offset >= 4 {
+			n = 1
+		}
+		page := make([]Position, n)
+		for i := range page {
+			page[i].Asset = strconv.Itoa(offset + i)
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+
+	positions, err := client.GetAllPositions(context.Background(), &GetPositionsParams{User: "0x1", Limit: 2})
+	if err != nil {
+		t.Fatalf("GetAllPositions failed: %v", err)
+	}
+	if len(positions) != 5 || positions[4].Asset != "4" {
+		t.Errorf("positions = %+v", positions)
+	}
+}
@@ -0,0 +1,132 @@
+// Package portfolio aggregates positions into exposure reports.
+package portfolio
+
+import (
+	"sort"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// StandardGroup is the negative-risk group key for positions in standard (non negative-risk) markets
+const StandardGroup = "standard"
+
+// Exposure is the aggregate of a group of positions.
+// Cost basis is Size × AvgPrice and current value is Size × CurPrice.
+type Exposure struct {
+	Key           string          // Group key: event slug, condition ID, outcome, wallet address or negative-risk group
+	Label         string          // Human-readable name of the group, e.g. the market title
+	Positions     int             // Number of positions in the group
+	Size          decimal.Decimal // Total tokens held
+	CostBasis     decimal.Decimal // Total cost of the tokens held
+	CurrentValue  decimal.Decimal // Total value at current prices
+	UnrealizedPnl decimal.Decimal // CurrentValue - CostBasis
+	Share         decimal.Decimal // Fraction of the portfolio's current value, between 0 and 1
+}
+
+// Report holds the exposure of a portfolio broken down by several dimensions.
+// Groups are sorted by current value, largest first.
+type Report struct {
+	Total          Exposure
+	ByWallet       []Exposure // Keyed by ProxyWallet
+	ByEvent        []Exposure // Keyed by EventSlug
+	ByMarket       []Exposure // Keyed by ConditionId
+	ByOutcome      []Exposure // Keyed by Outcome, e.g. "Yes" or "No"
+	ByNegRiskGroup []Exposure // Keyed by EventSlug for negative-risk markets, StandardGroup otherwise
+}
+
+// Aggregate builds an exposure report from positions
+func Aggregate(positions []polymarketdata.Position) *Report {
+	total := &Exposure{Key: "total", Label: "Total"}
+	byWallet := newGroups()
+	byEvent := newGroups()
+	byMarket := newGroups()
+	byOutcome := newGroups()
+	byNegRisk := newGroups()
+
+	for _, p := range positions {
+		total.add(p)
+		byWallet.add(p.ProxyWallet, p.ProxyWallet, p)
+		byEvent.add(p.EventSlug, p.EventSlug, p)
+		byMarket.add(p.ConditionId, p.Title, p)
+		byOutcome.add(p.Outcome, p.Outcome, p)
+		if p.NegativeRisk {
+			byNegRisk.add(p.EventSlug, p.EventSlug, p)
+		} else {
+			byNegRisk.add(StandardGroup, "Standard markets", p)
+		}
+	}
+
+	total.finish(total.CurrentValue)
+	return &Report{
+		Total:          *total,
+		ByWallet:       byWallet.list(total.CurrentValue),
+		ByEvent:        byEvent.list(total.CurrentValue),
+		ByMarket:       byMarket.list(total.CurrentValue),
+		ByOutcome:      byOutcome.list(total.CurrentValue),
+		ByNegRiskGroup: byNegRisk.list(total.CurrentValue),
+	}
+}
+
+// Combine builds a single team report from the positions of several wallets
+func Combine(wallets ...[]polymarketdata.Position) *Report {
+	var all []polymarketdata.Position
+	for _, positions := range wallets {
+		all = append(all, positions...)
+	}
+	return Aggregate(all)
+}
+
+func (e *Exposure) add(p polymarketdata.Position) {
+	e.Positions++
+	e.Size = e.Size.Add(p.Size)
+	e.CostBasis = e.CostBasis.Add(p.Size.Mul(p.AvgPrice))
+	e.CurrentValue = e.CurrentValue.Add(p.Size.Mul(p.CurPrice))
+}
+
+func (e *Exposure) finish(totalValue decimal.Decimal) {
+	e.UnrealizedPnl = e.CurrentValue.Sub(e.CostBasis)
+	if totalValue.IsPositive() {
+		e.Share = e.CurrentValue.Div(totalValue)
+	} else {
+		e.Share = decimal.Zero
+	}
+}
+
+// groups accumulates exposures by key, keeping first-seen order for stable sorting
+type groups struct {
+	index map[string]int
+	items []*Exposure
+}
+
+func newGroups() *groups {
+	return &groups{index: make(map[string]int)}
+}
+
+func (g *groups) add(key, label string, p polymarketdata.Position) {
+	i, ok := g.index[key]
+	if !ok {
+		i = len(g.items)
+		g.index[key] = i
+		g.items = append(g.items, &Exposure{Key: key, Label: label})
+	}
+	if g.items[i].Label == "" {
+		g.items[i].Label = label
+	}
+	g.items[i].add(p)
+}
+
+func (g *groups) list(totalValue decimal.Decimal) []Exposure {
+	out := make([]Exposure, len(g.items))
+	for i, e := range g.items {
+		e.finish(totalValue)
+		out[i] = *e
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if c := out[i].CurrentValue.Cmp(out[j].CurrentValue); c != 0 {
+			return c > 0
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
@@ -0,0 +1,91 @@
+package portfolio
+
+import (
+	"testing"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func position(wallet, event, condition, outcome string, negRisk bool, size, avg, cur string) polymarketdata.Position {
+	return polymarketdata.Position{
+		ProxyWallet:  wallet,
+		ConditionId:  condition,
+		Size:         decimal.RequireFromString(size),
+		AvgPrice:     decimal.RequireFromString(avg),
+		CurPrice:     decimal.RequireFromString(cur),
+		NegativeRisk: negRisk,
+		MarketRef:    polymarketdata.MarketRef{Title: "Market " + condition, EventSlug: event, Outcome: outcome},
+	}
+}
+
+func find(t *testing.T, exposures []Exposure, key string) Exposure {
+	t.Helper()
+	for _, e := range exposures {
+		if e.Key == key {
+			return e
+		}
+	}
+	t.Fatalf("group %q not found in %+v", key, exposures)
+	return Exposure{}
+}
+
+func TestAggregate(t *testing.T) {
+	report := Aggregate([]polymarketdata.Position{
+		position("0x1", "election", "0xa", "Yes", true, "100", "0.4", "0.5"),
+		position("0x1", "election", "0xb", "No", true, "50", "0.6", "0.2"),
+		position("0x1", "weather", "0xc", "Yes", false, "10", "0.5", "0.5"),
+	})
+
+	total := report.Total
+	if !total.CostBasis.Equal(decimal.RequireFromString("75")) || !total.CurrentValue.Equal(decimal.RequireFromString("65")) {
+		t.Errorf("total = %+v", total)
+	}
+	if !total.UnrealizedPnl.Equal(decimal.RequireFromString("-10")) || !total.Share.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("total pnl/share = %s/%s", total.UnrealizedPnl, total.Share)
+	}
+
+	election := find(t, report.ByEvent, "election")
+	if election.Positions != 2 || !election.UnrealizedPnl.Equal(decimal.RequireFromString("-10")) {
+		t.Errorf("election = %+v", election)
+	}
+	if report.ByEvent[0].Key != "election" {
+		t.Errorf("largest event should come first, got %s", report.ByEvent[0].Key)
+	}
+
+	market := find(t, report.ByMarket, "0xa")
+	if market.Label != "Market 0xa" || !market.Share.Equal(decimal.NewFromInt(50).Div(decimal.NewFromInt(65))) {
+		t.Errorf("market 0xa = %+v", market)
+	}
+
+	yes := find(t, report.ByOutcome, "Yes")
+	if yes.Positions != 2 || !yes.Size.Equal(decimal.NewFromInt(110)) {
+		t.Errorf("Yes = %+v", yes)
+	}
+
+	if find(t, report.ByNegRiskGroup, "election").Positions != 2 || find(t, report.ByNegRiskGroup, StandardGroup).Positions != 1 {
+		t.Errorf("negative-risk groups = %+v", report.ByNegRiskGroup)
+	}
+}
+
+func TestCombineWallets(t *testing.T) {
+	report := Combine(
+		[]polymarketdata.Position{position("0x1", "e", "0xa", "Yes", false, "10", "0.5", "0.6")},
+		[]polymarketdata.Position{position("0x2", "e", "0xa", "Yes", false, "30", "0.4", "0.6")},
+	)
+
+	if len(report.ByWallet) != 2 || report.ByWallet[0].Key != "0x2" {
+		t.Errorf("wallets = %+v", report.ByWallet)
+	}
+	market := find(t, report.ByMarket, "0xa")
+	if market.Positions != 2 || !market.CostBasis.Equal(decimal.NewFromInt(17)) || !market.UnrealizedPnl.Equal(decimal.NewFromInt(7)) {
+		t.Errorf("combined market = %+v", market)
+	}
+}
+
+func TestAggregateEmpty(t *testing.T) {
+	report := Aggregate(nil)
+	if report.Total.Positions != 0 || !report.Total.Share.IsZero() || len(report.ByMarket) != 0 {
+		t.Errorf("empty report = %+v", report)
+	}
+}
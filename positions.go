@@ -106,6 +106,8 @@ func (c *Client) GetPositions(ctx context.Context, params *GetPositionsParams) (
 		return nil, fmt.Errorf("failed to decode positions response: %w", err)
 	}
 
+	c.Directory().ObservePositions(positions)
+
 	return positions, nil
 }
 
@@ -197,6 +199,8 @@ func (c *Client) GetClosedPositions(ctx context.Context, params *GetClosedPositi
 		return nil, fmt.Errorf("failed to decode closed-positions response: %w", err)
 	}
 
+	c.Directory().ObserveClosedPositions(closedPositions)
+
 	return closedPositions, nil
 }
 
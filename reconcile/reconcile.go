@@ -0,0 +1,214 @@
+// Package reconcile compares API-reported position figures with values
+// recomputed independently from a wallet's activity history.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/ivanzzeth/polymarket-go-data-client/ledger"
+	"github.com/shopspring/decimal"
+)
+
+// Field names a reconciled figure
+type Field string
+
+const (
+	FieldSize        Field = "size"
+	FieldAvgPrice    Field = "avgPrice"
+	FieldRealizedPnl Field = "realizedPnl"
+	FieldCashPnl     Field = "cashPnl"
+)
+
+// Source names the API response a reported figure came from
+type Source string
+
+const (
+	SourcePosition       Source = "position"
+	SourceClosedPosition Source = "closedPosition"
+)
+
+// Cause is a likely explanation for a discrepancy
+type Cause string
+
+const (
+	CauseMissingActivity Cause = "missing_activity" // The history lacks acquisitions or the asset entirely
+	CauseConversion      Cause = "conversion"       // Negative-risk conversions touch the market
+	CauseReward          Cause = "reward"           // Rewards were paid that the API may count as PnL
+)
+
+// Tolerance sets the absolute differences below which figures are considered equal
+type Tolerance struct {
+	Size  decimal.Decimal
+	Price decimal.Decimal
+	Pnl   decimal.Decimal
+}
+
+// DefaultTolerance allows for the rounding the API applies to its figures
+var DefaultTolerance = Tolerance{
+	Size:  decimal.RequireFromString("0.01"),
+	Price: decimal.RequireFromString("0.005"),
+	Pnl:   decimal.RequireFromString("0.05"),
+}
+
+// Options configures a reconciliation
+type Options struct {
+	Tolerance *Tolerance    // Optional: Default DefaultTolerance
+	Method    ledger.Method // Optional: Default ledger.MethodAverage, which matches how the API reports AvgPrice
+}
+
+// Check is a single compared figure
+type Check struct {
+	Asset        string
+	ConditionId  string
+	OutcomeIndex int
+	Title        string
+	Source       Source
+	Field        Field
+	Reported     decimal.Decimal
+	Computed     decimal.Decimal
+	Difference   decimal.Decimal // Computed - Reported
+	Causes       []Cause         // Likely causes, only set for discrepancies
+}
+
+// Report is the outcome of a reconciliation
+type Report struct {
+	User          string
+	Checks        int     // Number of figures compared
+	Discrepancies []Check // Figures that differ by more than the tolerance
+}
+
+// Reconcile fetches a wallet's positions, closed positions and full activity
+// history and compares the reported figures with recomputed ones
+func Reconcile(ctx context.Context, client *polymarketdata.Client, user string, opts *Options) (*Report, error) {
+	zero := decimal.Zero
+	positions, err := client.GetAllPositions(ctx, &polymarketdata.GetPositionsParams{User: user, SizeThreshold: &zero})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch positions: %w", err)
+	}
+	closed, err := client.GetAllClosedPositions(ctx, &polymarketdata.GetClosedPositionsParams{User: user})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch closed positions: %w", err)
+	}
+	activity, err := client.GetAllActivity(ctx, &polymarketdata.GetActivityParams{User: user})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch activity: %w", err)
+	}
+
+	report, err := Compare(positions, closed, activity, opts)
+	if err != nil {
+		return nil, err
+	}
+	report.User = user
+	return report, nil
+}
+
+// Compare recomputes size, average price and PnL per asset from activity and
+// reports where they differ from the given positions and closed positions
+func Compare(positions []polymarketdata.Position, closed []polymarketdata.ClosedPosition, activity []polymarketdata.Activity, opts *Options) (*Report, error) {
+	tol := DefaultTolerance
+	method := ledger.MethodAverage
+	if opts != nil {
+		if opts.Tolerance != nil {
+			tol = *opts.Tolerance
+		}
+		if opts.Method != "" {
+			method = opts.Method
+		}
+	}
+
+	l, err := ledger.New(method)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.Replay(activity); err != nil {
+		return nil, fmt.Errorf("failed to replay activity: %w", err)
+	}
+
+	c := &comparer{
+		report:      &Report{},
+		seen:        make(map[ledger.Key]bool),
+		unmatched:   make(map[ledger.Key]bool),
+		conversions: make(map[string]bool),
+		rewards:     make(map[string]bool),
+	}
+	for _, a := range activity {
+		switch a.Type {
+		case polymarketdata.ActivityTypeConversion:
+			c.conversions[a.ConditionId] = true
+		case polymarketdata.ActivityTypeReward:
+			c.rewards[a.ConditionId] = true
+		}
+	}
+	for _, d := range l.Disposals() {
+		if d.Unmatched {
+			c.unmatched[d.Key] = true
+		}
+	}
+
+	for _, p := range positions {
+		key := ledger.Key{ConditionId: p.ConditionId, OutcomeIndex: p.OutcomeIndex}
+		h, ok := l.Holding(key)
+		c.seen[key] = ok
+		ref := Check{Asset: p.Asset, ConditionId: p.ConditionId, OutcomeIndex: p.OutcomeIndex, Title: p.Title, Source: SourcePosition}
+
+		c.check(ref, FieldSize, p.Size, h.Size, tol.Size)
+		c.check(ref, FieldAvgPrice, p.AvgPrice, h.AvgPrice, tol.Price)
+		c.check(ref, FieldRealizedPnl, p.RealizedPnl, h.RealizedPnl, tol.Pnl)
+		c.check(ref, FieldCashPnl, p.CashPnl, h.Size.Mul(p.CurPrice).Sub(h.CostBasis), tol.Pnl)
+	}
+
+	for _, p := range closed {
+		key := ledger.Key{ConditionId: p.ConditionId, OutcomeIndex: p.OutcomeIndex}
+		h, ok := l.Holding(key)
+		c.seen[key] = ok
+		ref := Check{Asset: p.Asset, ConditionId: p.ConditionId, OutcomeIndex: p.OutcomeIndex, Title: p.Title, Source: SourceClosedPosition}
+
+		c.check(ref, FieldSize, decimal.Zero, h.Size, tol.Size)
+		c.check(ref, FieldRealizedPnl, p.RealizedPnl, h.RealizedPnl, tol.Pnl)
+	}
+
+	return c.report, nil
+}
+
+type comparer struct {
+	report      *Report
+	seen        map[ledger.Key]bool // Whether the activity history mentions the asset
+	unmatched   map[ledger.Key]bool
+	conversions map[string]bool
+	rewards     map[string]bool
+}
+
+func (c *comparer) check(ref Check, field Field, reported, computed, tolerance decimal.Decimal) {
+	c.report.Checks++
+	diff := computed.Sub(reported)
+	if diff.Abs().LessThanOrEqual(tolerance) {
+		return
+	}
+
+	ref.Field = field
+	ref.Reported = reported
+	ref.Computed = computed
+	ref.Difference = diff
+	ref.Causes = c.causes(ref, diff)
+	c.report.Discrepancies = append(c.report.Discrepancies, ref)
+}
+
+func (c *comparer) causes(ref Check, diff decimal.Decimal) []Cause {
+	key := ledger.Key{ConditionId: ref.ConditionId, OutcomeIndex: ref.OutcomeIndex}
+	var causes []Cause
+	switch {
+	case !c.seen[key], c.unmatched[key]:
+		causes = append(causes, CauseMissingActivity)
+	case ref.Field == FieldSize && diff.IsNegative():
+		causes = append(causes, CauseMissingActivity)
+	}
+	if c.conversions[ref.ConditionId] {
+		causes = append(causes, CauseConversion)
+	}
+	if (ref.Field == FieldRealizedPnl || ref.Field == FieldCashPnl) && (c.rewards[ref.ConditionId] || c.rewards[""]) {
+		causes = append(causes, CauseReward)
+	}
+	return causes
+}
@@ -0,0 +1,137 @@
+package reconcile
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func buy(ts int64, condition string, size, price string) polymarketdata.Activity {
+	return polymarketdata.Activity{
+		Timestamp:   ts,
+		Type:        polymarketdata.ActivityTypeTrade,
+		Side:        polymarketdata.TradeSideBuy,
+		ConditionId: condition,
+		Asset:       condition + "-yes",
+		Size:        d(size),
+		Price:       d(price),
+		UsdcSize:    d(size).Mul(d(price)),
+	}
+}
+
+func hasCause(c Check, cause Cause) bool {
+	for _, got := range c.Causes {
+		if got == cause {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCompareMatches(t *testing.T) {
+	activity := []polymarketdata.Activity{
+		buy(1, "0xa", "10", "0.4"),
+		buy(2, "0xa", "10", "0.6"),
+	}
+	positions := []polymarketdata.Position{{
+		ConditionId: "0xa",
+		Asset:       "0xa-yes",
+		Size:        d("20"),
+		AvgPrice:    d("0.5"),
+		CurPrice:    d("0.7"),
+		CashPnl:     d("4"),
+	}}
+
+	report, err := Compare(positions, nil, activity, nil)
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+	if report.Checks != 4 || len(report.Discrepancies) != 0 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestCompareFlagsDiscrepancies(t *testing.T) {
+	activity := []polymarketdata.Activity{
+		buy(1, "0xa", "10", "0.5"),
+		{Timestamp: 2, Type: polymarketdata.ActivityTypeConversion, ConditionId: "0xb", Size: d("5"), UsdcSize: d("1")},
+		{Timestamp: 3, Type: polymarketdata.ActivityTypeReward, ConditionId: "0xa", UsdcSize: d("2")},
+	}
+	positions := []polymarketdata.Position{
+		// The API knows about 5 more tokens than the history shows
+		{ConditionId: "0xa", Size: d("15"), AvgPrice: d("0.5"), CurPrice: d("0.5"), RealizedPnl: d("2")},
+	}
+	closed := []polymarketdata.ClosedPosition{
+		{ConditionId: "0xb", RealizedPnl: d("3")},
+		{ConditionId: "0xc", RealizedPnl: d("1")},
+	}
+
+	report, err := Compare(positions, closed, activity, &Options{Tolerance: &Tolerance{Size: d("0.1"), Price: d("0.01"), Pnl: d("0.1")}})
+	if err != nil {
+		t.Fatalf("Compare failed: %v", err)
+	}
+
+	byKey := make(map[string]Check)
+	for _, c := range report.Discrepancies {
+		byKey[c.ConditionId+"/"+string(c.Field)] = c
+	}
+
+	size, ok := byKey["0xa/size"]
+	if !ok || !size.Difference.Equal(d("-5")) || !hasCause(size, CauseMissingActivity) {
+		t.Errorf("size discrepancy = %+v", size)
+	}
+	if pnl, ok := byKey["0xa/realizedPnl"]; !ok || !hasCause(pnl, CauseReward) {
+		t.Errorf("realized pnl discrepancy = %+v", pnl)
+	}
+	if conv, ok := byKey["0xb/realizedPnl"]; !ok || !hasCause(conv, CauseConversion) || !hasCause(conv, CauseMissingActivity) {
+		t.Errorf("conversion discrepancy = %+v", conv)
+	}
+	if missing, ok := byKey["0xc/realizedPnl"]; !ok || !hasCause(missing, CauseMissingActivity) {
+		t.Errorf("missing asset discrepancy = %+v", missing)
+	}
+}
+
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+func TestReconcileFetches(t *testing.T) {
+	client, err := polymarketdata.NewClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/positions":
+			if r.URL.Query().Get("sizeThreshold") != "0" {
+				t.Errorf("positions should be fetched without a size threshold")
+			}
+			w.Write([]byte(`[{"conditionId":"0xa","size":"10","avgPrice":"0.5","curPrice":"0.5"}]`))
+		case "/closed-positions":
+			w.Write([]byte(`[]`))
+		case "/activity":
+			w.Write([]byte(`[{"timestamp":1,"type":"TRADE","side":"BUY","conditionId":"0xa","size":"10","price":"0.5","usdcSize":"5"}]`))
+		}
+	})}})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	report, err := Reconcile(context.Background(), client, "0x1", nil)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if report.User != "0x1" || report.Checks != 4 || len(report.Discrepancies) != 0 {
+		t.Errorf("report = %+v", report)
+	}
+}
@@ -0,0 +1,243 @@
+// Package scoring rates a wallet's trading skill from its closed and open
+// positions, with sample-size adjustments so that wallets with few
+// positions can be compared with wallets with many.
+package scoring
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// precision is the number of decimal places kept by divisions
+const precision = 16
+
+// DefaultPriorPositions is the number of breakeven positions a score is shrunk towards
+const DefaultPriorPositions = 20
+
+// DefaultZ is the z-score of the Wilson lower bound, for 95% confidence
+const DefaultZ = 1.96
+
+// Options configures scoring
+type Options struct {
+	IncludeOpen    bool    // Optional: Also count unresolved open positions at their current value. Redeemable positions always count
+	PriorPositions int     // Optional: Strength of the shrinkage towards zero ROI. Default DefaultPriorPositions
+	Z              float64 // Optional: Z-score of the win rate lower bound. Default DefaultZ
+}
+
+// Score holds a wallet's performance metrics. Each scored position is a closed
+// position, a redeemable position or, with IncludeOpen, an open position.
+type Score struct {
+	User          string
+	Positions     int // Scored positions
+	Wins          int // Positions with a positive PnL
+	Losses        int // Positions with a negative PnL
+	OpenPositions int // Unresolved open positions, whether scored or not
+
+	Cost         decimal.Decimal // Sum of TotalBought × AvgPrice
+	Pnl          decimal.Decimal
+	GrossProfit  decimal.Decimal // Sum of positive PnL
+	GrossLoss    decimal.Decimal // Sum of negative PnL, as a positive number
+	WinRate      decimal.Decimal // Wins / Positions
+	WinRateLower decimal.Decimal // Wilson score lower bound of the win rate
+	ROI          decimal.Decimal // Pnl / Cost
+	ProfitFactor decimal.Decimal // GrossProfit / GrossLoss, zero if there are no losses; Rank puts profit without losses first
+	AvgEdge      decimal.Decimal // Average exit price minus entry AvgPrice per token bought: Pnl / sum of TotalBought
+
+	Confidence decimal.Decimal // Positions / (Positions + PriorPositions), between 0 and 1
+	ShrunkROI  decimal.Decimal // ROI × Confidence: the default ranking metric
+}
+
+// Compute scores a wallet from its closed and open positions
+func Compute(user string, closed []polymarketdata.ClosedPosition, positions []polymarketdata.Position, opts *Options) Score {
+	includeOpen := false
+	prior := DefaultPriorPositions
+	z := DefaultZ
+	if opts != nil {
+		includeOpen = opts.IncludeOpen
+		if opts.PriorPositions > 0 {
+			prior = opts.PriorPositions
+		}
+		if opts.Z > 0 {
+			z = opts.Z
+		}
+	}
+
+	s := Score{User: user}
+	var tokens decimal.Decimal
+	add := func(bought, avgPrice, pnl decimal.Decimal) {
+		s.Positions++
+		s.Cost = s.Cost.Add(bought.Mul(avgPrice))
+		s.Pnl = s.Pnl.Add(pnl)
+		tokens = tokens.Add(bought)
+		switch pnl.Sign() {
+		case 1:
+			s.Wins++
+			s.GrossProfit = s.GrossProfit.Add(pnl)
+		case -1:
+			s.Losses++
+			s.GrossLoss = s.GrossLoss.Sub(pnl)
+		}
+	}
+
+	for _, p := range closed {
+		add(p.TotalBought, p.AvgPrice, p.RealizedPnl)
+	}
+	for _, p := range positions {
+		if !p.Redeemable {
+			s.OpenPositions++
+			if !includeOpen {
+				continue
+			}
+		}
+		add(p.TotalBought, p.AvgPrice, p.RealizedPnl.Add(p.CashPnl))
+	}
+
+	if s.Positions == 0 {
+		return s
+	}
+	n := decimal.NewFromInt(int64(s.Positions))
+	s.WinRate = decimal.NewFromInt(int64(s.Wins)).DivRound(n, precision)
+	s.WinRateLower = wilsonLower(s.Wins, s.Positions, z)
+	if s.Cost.IsPositive() {
+		s.ROI = s.Pnl.DivRound(s.Cost, precision)
+	}
+	if s.GrossLoss.IsPositive() {
+		s.ProfitFactor = s.GrossProfit.DivRound(s.GrossLoss, precision)
+	}
+	if tokens.IsPositive() {
+		s.AvgEdge = s.Pnl.DivRound(tokens, precision)
+	}
+	s.Confidence = n.DivRound(n.Add(decimal.NewFromInt(int64(prior))), precision)
+	s.ShrunkROI = s.ROI.Mul(s.Confidence).Round(precision)
+	return s
+}
+
+// Fetch retrieves every closed and open position of a wallet and scores it
+func Fetch(ctx context.Context, client *polymarketdata.Client, user string, opts *Options) (*Score, error) {
+	closed, err := client.GetAllClosedPositions(ctx, &polymarketdata.GetClosedPositionsParams{User: user})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch closed positions: %w", err)
+	}
+	positions, err := client.GetAllPositions(ctx, &polymarketdata.GetPositionsParams{User: user})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch positions: %w", err)
+	}
+	s := Compute(user, closed, positions, opts)
+	return &s, nil
+}
+
+// Metric selects the value wallets are ranked by
+type Metric string
+
+const (
+	MetricShrunkROI    Metric = "SHRUNK_ROI"
+	MetricROI          Metric = "ROI"
+	MetricPnl          Metric = "PNL"
+	MetricWinRate      Metric = "WIN_RATE"
+	MetricWinRateLower Metric = "WIN_RATE_LOWER"
+	MetricProfitFactor Metric = "PROFIT_FACTOR"
+	MetricAvgEdge      Metric = "AVG_EDGE"
+)
+
+// Value returns the score's value for a metric
+func (s Score) Value(metric Metric) (decimal.Decimal, error) {
+	switch metric {
+	case MetricShrunkROI, "":
+		return s.ShrunkROI, nil
+	case MetricROI:
+		return s.ROI, nil
+	case MetricPnl:
+		return s.Pnl, nil
+	case MetricWinRate:
+		return s.WinRate, nil
+	case MetricWinRateLower:
+		return s.WinRateLower, nil
+	case MetricProfitFactor:
+		return s.ProfitFactor, nil
+	case MetricAvgEdge:
+		return s.AvgEdge, nil
+	default:
+		return decimal.Zero, fmt.Errorf("unknown metric: %s", metric)
+	}
+}
+
+// Ranked is a score with its position in a ranking
+type Ranked struct {
+	Rank      int // Starts at 1. Wallets with equal values share a rank
+	Value     decimal.Decimal
+	Unbounded bool // Ranked by MetricProfitFactor with profit and no losses: Value is zero but ranks above every other value
+	Score
+}
+
+// Rank orders scores by a metric, highest first. Ties are broken by the number
+// of positions, then by wallet address, so the order is reproducible regardless
+// of the input order. An empty metric ranks by MetricShrunkROI. By
+// MetricProfitFactor, wallets with profit and no losses rank first, ordered by
+// gross profit.
+func Rank(scores []Score, metric Metric) ([]Ranked, error) {
+	out := make([]Ranked, 0, len(scores))
+	for _, s := range scores {
+		v, err := s.Value(metric)
+		if err != nil {
+			return nil, err
+		}
+		unbounded := metric == MetricProfitFactor && s.GrossProfit.IsPositive() && !s.GrossLoss.IsPositive()
+		out = append(out, Ranked{Value: v, Unbounded: unbounded, Score: s})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if c := compareValues(a, b); c != 0 {
+			return c > 0
+		}
+		if a.Positions != b.Positions {
+			return a.Positions > b.Positions
+		}
+		return strings.ToLower(a.User) < strings.ToLower(b.User)
+	})
+
+	for i := range out {
+		if i > 0 && compareValues(out[i], out[i-1]) == 0 {
+			out[i].Rank = out[i-1].Rank
+		} else {
+			out[i].Rank = i + 1
+		}
+	}
+	return out, nil
+}
+
+// compareValues compares the ranked values of two wallets, with unbounded values above the rest
+func compareValues(a, b Ranked) int {
+	switch {
+	case a.Unbounded && b.Unbounded:
+		return a.GrossProfit.Cmp(b.GrossProfit)
+	case a.Unbounded:
+		return 1
+	case b.Unbounded:
+		return -1
+	}
+	return a.Value.Cmp(b.Value)
+}
+
+// wilsonLower returns the lower bound of the Wilson score interval of a proportion
+func wilsonLower(wins, n int, z float64) decimal.Decimal {
+	if n == 0 {
+		return decimal.Zero
+	}
+	p := float64(wins) / float64(n)
+	nf := float64(n)
+	z2 := z * z
+	centre := p + z2/(2*nf)
+	margin := z * math.Sqrt(p*(1-p)/nf+z2/(4*nf*nf))
+	lower := (centre - margin) / (1 + z2/nf)
+	if lower < 0 {
+		lower = 0
+	}
+	return decimal.NewFromFloat(lower).Round(precision)
+}
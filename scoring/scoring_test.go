@@ -0,0 +1,183 @@
+package scoring
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func expectDecimal(t *testing.T, name string, got decimal.Decimal, want string) {
+	t.Helper()
+	if !got.Round(4).Equal(d(want)) {
+		t.Errorf("%s: got %s, want %s", name, got, want)
+	}
+}
+
+var (
+	closedFixture = []polymarketdata.ClosedPosition{
+		{TotalBought: d("100"), AvgPrice: d("0.4"), RealizedPnl: d("60")},
+		{TotalBought: d("50"), AvgPrice: d("0.6"), RealizedPnl: d("-30")},
+		{TotalBought: d("10"), AvgPrice: d("0.5"), RealizedPnl: d("0")},
+	}
+	positionsFixture = []polymarketdata.Position{
+		{TotalBought: d("20"), AvgPrice: d("0.5"), CashPnl: d("10"), Redeemable: true},
+		{TotalBought: d("40"), AvgPrice: d("0.25"), CashPnl: d("-5")},
+	}
+)
+
+func TestCompute(t *testing.T) {
+	s := Compute("0xa", closedFixture, positionsFixture, nil)
+	if s.Positions != 4 || s.Wins != 2 || s.Losses != 1 || s.OpenPositions != 1 {
+		t.Errorf("unexpected counts %d/%d/%d/%d", s.Positions, s.Wins, s.Losses, s.OpenPositions)
+	}
+	expectDecimal(t, "cost", s.Cost, "85")
+	expectDecimal(t, "pnl", s.Pnl, "40")
+	expectDecimal(t, "gross profit", s.GrossProfit, "70")
+	expectDecimal(t, "gross loss", s.GrossLoss, "30")
+	expectDecimal(t, "win rate", s.WinRate, "0.5")
+	expectDecimal(t, "win rate lower", s.WinRateLower, "0.15")
+	expectDecimal(t, "roi", s.ROI, "0.4706")
+	expectDecimal(t, "profit factor", s.ProfitFactor, "2.3333")
+	expectDecimal(t, "avg edge", s.AvgEdge, "0.2222")
+	expectDecimal(t, "confidence", s.Confidence, "0.1667")
+	expectDecimal(t, "shrunk roi", s.ShrunkROI, "0.0784")
+
+	open := Compute("0xa", closedFixture, positionsFixture, &Options{IncludeOpen: true, PriorPositions: 5})
+	if open.Positions != 5 || open.Losses != 2 {
+		t.Errorf("expected the open position to be scored, got %d positions", open.Positions)
+	}
+	expectDecimal(t, "open pnl", open.Pnl, "35")
+	expectDecimal(t, "open confidence", open.Confidence, "0.5")
+
+	empty := Compute("0xb", nil, nil, nil)
+	if empty.Positions != 0 || !empty.ShrunkROI.IsZero() || !empty.WinRateLower.IsZero() {
+		t.Errorf("unexpected score without positions %+v", empty)
+	}
+}
+
+func TestSampleSizeAdjustment(t *testing.T) {
+	lucky := Compute("0xlucky", []polymarketdata.ClosedPosition{
+		{TotalBought: d("10"), AvgPrice: d("0.5"), RealizedPnl: d("5")},
+	}, nil, nil)
+
+	var many []polymarketdata.ClosedPosition
+	for i := 0; i < 100; i++ {
+		pnl := "8"
+		if i%3 == 0 {
+			pnl = "-10"
+		}
+		many = append(many, polymarketdata.ClosedPosition{TotalBought: d("20"), AvgPrice: d("0.5"), RealizedPnl: d(pnl)})
+	}
+	skilled := Compute("0xskilled", many, nil, nil)
+
+	if !lucky.ROI.GreaterThan(skilled.ROI) {
+		t.Fatal("fixture should give the lucky wallet the higher raw ROI")
+	}
+	if !skilled.ShrunkROI.GreaterThan(lucky.ShrunkROI) {
+		t.Errorf("expected the larger sample to score higher: %s vs %s", skilled.ShrunkROI, lucky.ShrunkROI)
+	}
+	if !skilled.WinRateLower.GreaterThan(lucky.WinRateLower) {
+		t.Errorf("expected a tighter win rate bound for the larger sample: %s vs %s", skilled.WinRateLower, lucky.WinRateLower)
+	}
+}
+
+func TestRank(t *testing.T) {
+	scores := []Score{
+		{User: "0xc", Positions: 5, ShrunkROI: d("0.1"), Pnl: d("10")},
+		{User: "0xB", Positions: 5, ShrunkROI: d("0.2"), Pnl: d("10")},
+		{User: "0xa", Positions: 5, ShrunkROI: d("0.2"), Pnl: d("30")},
+		{User: "0xd", Positions: 9, ShrunkROI: d("0.2"), Pnl: d("10")},
+	}
+
+	ranked, err := Rank(scores, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantUsers := []string{"0xd", "0xa", "0xB", "0xc"}
+	wantRanks := []int{1, 1, 1, 4}
+	for i := range ranked {
+		if ranked[i].User != wantUsers[i] || ranked[i].Rank != wantRanks[i] {
+			t.Errorf("position %d: got %s rank %d, want %s rank %d", i, ranked[i].User, ranked[i].Rank, wantUsers[i], wantRanks[i])
+		}
+	}
+
+	// Reproducible regardless of input order
+	reversed := []Score{scores[3], scores[2], scores[1], scores[0]}
+	again, _ := Rank(reversed, MetricShrunkROI)
+	for i := range again {
+		if again[i].User != ranked[i].User {
+			t.Errorf("position %d differs between input orders: %s vs %s", i, again[i].User, ranked[i].User)
+		}
+	}
+
+	byPnl, _ := Rank(scores, MetricPnl)
+	if byPnl[0].User != "0xa" || !byPnl[0].Value.Equal(d("30")) {
+		t.Errorf("unexpected pnl ranking %+v", byPnl[0])
+	}
+
+	if _, err := Rank(scores, "SHARPE"); err == nil {
+		t.Error("expected an error for an unknown metric")
+	}
+}
+
+func TestRankProfitFactorWithoutLosses(t *testing.T) {
+	scores := []Score{
+		{User: "0xa", Positions: 3, GrossProfit: d("30"), GrossLoss: d("10"), ProfitFactor: d("3")},
+		{User: "0xb", Positions: 2, GrossProfit: d("5")},
+		{User: "0xc", Positions: 2, GrossProfit: d("20")},
+		{User: "0xd", Positions: 1},
+	}
+	ranked, err := Rank(scores, MetricProfitFactor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantUsers := []string{"0xc", "0xb", "0xa", "0xd"}
+	for i := range ranked {
+		if ranked[i].User != wantUsers[i] || ranked[i].Rank != i+1 {
+			t.Errorf("position %d: got %s rank %d, want %s rank %d", i, ranked[i].User, ranked[i].Rank, wantUsers[i], i+1)
+		}
+	}
+	if !ranked[0].Unbounded || ranked[2].Unbounded || ranked[3].Unbounded {
+		t.Errorf("expected only wallets with profit and no losses to be unbounded, got %+v", ranked)
+	}
+}
+
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+func TestFetch(t *testing.T) {
+	client, err := polymarketdata.NewClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/closed-positions":
+			w.Write([]byte(`[{"totalBought":"10","avgPrice":"0.5","realizedPnl":"5"}]`))
+		case "/positions":
+			w.Write([]byte(`[{"totalBought":"10","avgPrice":"0.5","cashPnl":"-5","redeemable":true}]`))
+		}
+	})}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Fetch(context.Background(), client, "0xa", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.User != "0xa" || s.Positions != 2 || !s.Pnl.IsZero() {
+		t.Errorf("unexpected score %+v", s)
+	}
+}
@@ -0,0 +1,128 @@
+package polymarketdata
+
+import (
+	"context"
+	"strings"
+)
+
+// Store persists API results locally so that later runs only need to fetch deltas.
+// Trades and activity are upserted by transaction-level keys (see TradeKey and
+// ActivityKey); snapshots and open interest readings are upserted by their
+// subject and timestamp. Query results are returned in ascending timestamp
+// order, or in descending order if the query asks for it.
+type Store interface {
+	// UpsertTrades stores trades and returns how many were not stored before
+	UpsertTrades(ctx context.Context, trades []Trade) (int, error)
+	QueryTrades(ctx context.Context, query *StoreQuery) ([]Trade, error)
+
+	// UpsertActivity stores activity and returns how many records were not stored before
+	UpsertActivity(ctx context.Context, activities []Activity) (int, error)
+	QueryActivity(ctx context.Context, query *StoreQuery) ([]Activity, error)
+
+	SavePositionsSnapshot(ctx context.Context, snapshot *PositionsSnapshot) error
+	QueryPositionsSnapshots(ctx context.Context, query *StoreQuery) ([]PositionsSnapshot, error)
+
+	SaveClosedPositionsSnapshot(ctx context.Context, snapshot *ClosedPositionsSnapshot) error
+	QueryClosedPositionsSnapshots(ctx context.Context, query *StoreQuery) ([]ClosedPositionsSnapshot, error)
+
+	SaveHoldersSnapshot(ctx context.Context, snapshot *HoldersSnapshot) error
+	QueryHoldersSnapshots(ctx context.Context, query *StoreQuery) ([]HoldersSnapshot, error)
+
+	SaveOpenInterest(ctx context.Context, readings []OpenInterestReading) error
+	QueryOpenInterest(ctx context.Context, query *StoreQuery) ([]OpenInterestReading, error)
+
+	// GetSyncState returns the sync state of a wallet, or nil if it was never synced
+	GetSyncState(ctx context.Context, user string) (*SyncState, error)
+	SaveSyncState(ctx context.Context, state *SyncState) error
+
+	// Compact rewrites the underlying storage without superseded records
+	Compact(ctx context.Context) error
+	Close() error
+}
+
+// StoreQuery selects records from a Store. Zero values mean not set.
+type StoreQuery struct {
+	User        string // Optional: Proxy wallet address. Ignored for holders snapshots and open interest.
+	ConditionId string // Optional: Condition ID. Ignored for positions and closed positions snapshots.
+	Start       int64  // Optional: Inclusive start timestamp (Unix seconds)
+	End         int64  // Optional: Inclusive end timestamp (Unix seconds)
+	Limit       int    // Optional: Maximum number of records, keeping the earliest, or the latest if Descending
+	Descending  bool   // Optional: Return the latest records first
+}
+
+// PositionsSnapshot is the full set of a wallet's positions at a point in time
+type PositionsSnapshot struct {
+	User      string     `json:"user"`
+	Timestamp int64      `json:"timestamp"`
+	Positions []Position `json:"positions"`
+}
+
+// ClosedPositionsSnapshot is the full set of a wallet's closed positions at a point in time
+type ClosedPositionsSnapshot struct {
+	User            string           `json:"user"`
+	Timestamp       int64            `json:"timestamp"`
+	ClosedPositions []ClosedPosition `json:"closedPositions"`
+}
+
+// HoldersSnapshot is a GetHolders result for a market at a point in time
+type HoldersSnapshot struct {
+	ConditionId string          `json:"conditionId"`
+	Timestamp   int64           `json:"timestamp"`
+	Holders     []MarketHolders `json:"holders"`
+}
+
+// OpenInterestReading is an open interest value sampled at a point in time
+type OpenInterestReading struct {
+	Timestamp int64 `json:"timestamp"`
+	OpenInterest
+}
+
+// TradeKey identifies a trade fill. A single transaction can contain several
+// fills for the same wallet and asset, so size and price are part of the key.
+func TradeKey(t Trade) string {
+	return strings.Join([]string{
+		strings.ToLower(t.TransactionHash),
+		strings.ToLower(t.ProxyWallet),
+		t.Asset,
+		string(t.Side),
+		t.Size.String(),
+		t.Price.String(),
+	}, "|")
+}
+
+// ActivityKey identifies an activity record. Activity without an asset
+// (splits, merges, redemptions) is distinguished by condition ID and amounts.
+func ActivityKey(a Activity) string {
+	return strings.Join([]string{
+		strings.ToLower(a.TransactionHash),
+		strings.ToLower(a.ProxyWallet),
+		string(a.Type),
+		a.ConditionId,
+		a.Asset,
+		string(a.Side),
+		a.Size.String(),
+		a.UsdcSize.String(),
+	}, "|")
+}
+
+// matchTime reports whether ts falls within the query's time range. A nil query matches everything.
+func (q *StoreQuery) matchTime(ts int64) bool {
+	if q == nil {
+		return true
+	}
+	if q.Start > 0 && ts < q.Start {
+		return false
+	}
+	if q.End > 0 && ts > q.End {
+		return false
+	}
+	return true
+}
+
+func (q *StoreQuery) matchUser(user string) bool {
+	return q == nil || q.User == "" || strings.EqualFold(q.User, user)
+}
+
+func (q *StoreQuery) matchCondition(conditionId string) bool {
+	return q == nil || q.ConditionId == "" || strings.EqualFold(q.ConditionId, conditionId)
+}
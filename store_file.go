@@ -0,0 +1,584 @@
+package polymarketdata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FileStore is a dependency-free Store backed by append-only JSONL files in a
+// directory. All records are indexed in memory on open; upserts append to the
+// files and Compact rewrites them without superseded lines.
+type FileStore struct {
+	mu     sync.Mutex
+	dir    string
+	closed bool
+
+	trades          *fileCollection[Trade]
+	activity        *fileCollection[Activity]
+	positions       *fileCollection[PositionsSnapshot]
+	closedPositions *fileCollection[ClosedPositionsSnapshot]
+	holders         *fileCollection[HoldersSnapshot]
+	openInterest    *fileCollection[OpenInterestReading]
+	syncStates      *fileCollection[SyncState]
+	tailCursors     *fileCollection[TailCursor]
+}
+
+var (
+	_ Store           = (*FileStore)(nil)
+	_ TailCursorStore = (*FileStore)(nil)
+)
+
+// NewFileStore opens (or creates) a file-backed store in dir
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store directory: %w", err)
+	}
+
+	s := &FileStore{dir: dir}
+	var err error
+	if s.trades, err = openFileCollection(dir, "trades.jsonl", TradeKey, func(t *Trade) int64 { return t.Timestamp }); err != nil {
+		return nil, err
+	}
+	if s.activity, err = openFileCollection(dir, "activity.jsonl", ActivityKey, func(a *Activity) int64 { return a.Timestamp }); err != nil {
+		s.closeAll()
+		return nil, err
+	}
+	if s.positions, err = openFileCollection(dir, "positions.jsonl", func(p PositionsSnapshot) string {
+		return strings.ToLower(p.User) + "|" + strconv.FormatInt(p.Timestamp, 10)
+	}, func(p *PositionsSnapshot) int64 { return p.Timestamp }); err != nil {
+		s.closeAll()
+		return nil, err
+	}
+	if s.closedPositions, err = openFileCollection(dir, "closed_positions.jsonl", func(p ClosedPositionsSnapshot) string {
+		return strings.ToLower(p.User) + "|" + strconv.FormatInt(p.Timestamp, 10)
+	}, func(p *ClosedPositionsSnapshot) int64 { return p.Timestamp }); err != nil {
+		s.closeAll()
+		return nil, err
+	}
+	if s.holders, err = openFileCollection(dir, "holders.jsonl", func(h HoldersSnapshot) string {
+		return strings.ToLower(h.ConditionId) + "|" + strconv.FormatInt(h.Timestamp, 10)
+	}, func(h *HoldersSnapshot) int64 { return h.Timestamp }); err != nil {
+		s.closeAll()
+		return nil, err
+	}
+	if s.openInterest, err = openFileCollection(dir, "open_interest.jsonl", func(r OpenInterestReading) string {
+		return strings.ToLower(r.Market) + "|" + strconv.FormatInt(r.Timestamp, 10)
+	}, func(r *OpenInterestReading) int64 { return r.Timestamp }); err != nil {
+		s.closeAll()
+		return nil, err
+	}
+	if s.syncStates, err = openFileCollection(dir, "sync_state.jsonl", func(st SyncState) string {
+		return strings.ToLower(st.User)
+	}, func(st *SyncState) int64 { return st.LastRunAt }); err != nil {
+		s.closeAll()
+		return nil, err
+	}
+	if s.tailCursors, err = openFileCollection(dir, "tail_cursor.jsonl", func(c TailCursor) string {
+		return c.Key
+	}, func(c *TailCursor) int64 { return c.UpdatedAt }); err != nil {
+		s.closeAll()
+		return nil, err
+	}
+	return s, nil
+}
+
+// UpsertTrades stores trades keyed by TradeKey
+func (s *FileStore) UpsertTrades(ctx context.Context, trades []Trade) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return 0, err
+	}
+	return s.trades.upsert(trades)
+}
+
+// QueryTrades returns trades matching the query's wallet, condition ID and time range
+func (s *FileStore) QueryTrades(ctx context.Context, query *StoreQuery) ([]Trade, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	return s.trades.query(query, func(t *Trade) bool {
+		return query.matchUser(t.ProxyWallet) && query.matchCondition(t.ConditionId)
+	}), nil
+}
+
+// UpsertActivity stores activity keyed by ActivityKey
+func (s *FileStore) UpsertActivity(ctx context.Context, activities []Activity) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return 0, err
+	}
+	return s.activity.upsert(activities)
+}
+
+// QueryActivity returns activity matching the query's wallet, condition ID and time range
+func (s *FileStore) QueryActivity(ctx context.Context, query *StoreQuery) ([]Activity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	return s.activity.query(query, func(a *Activity) bool {
+		return query.matchUser(a.ProxyWallet) && query.matchCondition(a.ConditionId)
+	}), nil
+}
+
+// SavePositionsSnapshot stores a snapshot, replacing any snapshot for the same wallet and timestamp
+func (s *FileStore) SavePositionsSnapshot(ctx context.Context, snapshot *PositionsSnapshot) error {
+	if snapshot.User == "" {
+		return fmt.Errorf("user address is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return err
+	}
+	_, err := s.positions.upsert([]PositionsSnapshot{*snapshot})
+	return err
+}
+
+// QueryPositionsSnapshots returns snapshots matching the query's wallet and time range
+func (s *FileStore) QueryPositionsSnapshots(ctx context.Context, query *StoreQuery) ([]PositionsSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	return s.positions.query(query, func(p *PositionsSnapshot) bool {
+		return query.matchUser(p.User)
+	}), nil
+}
+
+// SaveClosedPositionsSnapshot stores a snapshot, replacing any snapshot for the same wallet and timestamp
+func (s *FileStore) SaveClosedPositionsSnapshot(ctx context.Context, snapshot *ClosedPositionsSnapshot) error {
+	if snapshot.User == "" {
+		return fmt.Errorf("user address is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return err
+	}
+	_, err := s.closedPositions.upsert([]ClosedPositionsSnapshot{*snapshot})
+	return err
+}
+
+// QueryClosedPositionsSnapshots returns snapshots matching the query's wallet and time range
+func (s *FileStore) QueryClosedPositionsSnapshots(ctx context.Context, query *StoreQuery) ([]ClosedPositionsSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	return s.closedPositions.query(query, func(p *ClosedPositionsSnapshot) bool {
+		return query.matchUser(p.User)
+	}), nil
+}
+
+// SaveHoldersSnapshot stores a snapshot, replacing any snapshot for the same market and timestamp
+func (s *FileStore) SaveHoldersSnapshot(ctx context.Context, snapshot *HoldersSnapshot) error {
+	if snapshot.ConditionId == "" {
+		return fmt.Errorf("condition ID is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return err
+	}
+	_, err := s.holders.upsert([]HoldersSnapshot{*snapshot})
+	return err
+}
+
+// QueryHoldersSnapshots returns snapshots matching the query's condition ID and time range
+func (s *FileStore) QueryHoldersSnapshots(ctx context.Context, query *StoreQuery) ([]HoldersSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	return s.holders.query(query, func(h *HoldersSnapshot) bool {
+		return query.matchCondition(h.ConditionId)
+	}), nil
+}
+
+// SaveOpenInterest stores readings, replacing any reading for the same market and timestamp
+func (s *FileStore) SaveOpenInterest(ctx context.Context, readings []OpenInterestReading) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return err
+	}
+	_, err := s.openInterest.upsert(readings)
+	return err
+}
+
+// QueryOpenInterest returns readings matching the query's condition ID and time range
+func (s *FileStore) QueryOpenInterest(ctx context.Context, query *StoreQuery) ([]OpenInterestReading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	return s.openInterest.query(query, func(r *OpenInterestReading) bool {
+		return query.matchCondition(r.Market)
+	}), nil
+}
+
+// GetSyncState returns the stored sync state of a wallet, or nil if there is none
+func (s *FileStore) GetSyncState(ctx context.Context, user string) (*SyncState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	state, ok := s.syncStates.get(strings.ToLower(user))
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// SaveSyncState stores the sync state of a wallet, replacing the previous one
+func (s *FileStore) SaveSyncState(ctx context.Context, state *SyncState) error {
+	if state.User == "" {
+		return fmt.Errorf("user address is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return err
+	}
+	_, err := s.syncStates.upsert([]SyncState{*state})
+	return err
+}
+
+// GetTailCursor returns the stored trade tail cursor with the given key, or nil if there is none
+func (s *FileStore) GetTailCursor(ctx context.Context, key string) (*TailCursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return nil, err
+	}
+	cursor, ok := s.tailCursors.get(key)
+	if !ok {
+		return nil, nil
+	}
+	return &cursor, nil
+}
+
+// SaveTailCursor stores a trade tail cursor, replacing the previous one with the same key
+func (s *FileStore) SaveTailCursor(ctx context.Context, cursor *TailCursor) error {
+	if cursor.Key == "" {
+		return fmt.Errorf("cursor key is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return err
+	}
+	_, err := s.tailCursors.upsert([]TailCursor{*cursor})
+	return err
+}
+
+// Compact rewrites every file so that it holds exactly one line per record
+func (s *FileStore) Compact(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(ctx); err != nil {
+		return err
+	}
+	for _, c := range []interface{ compact() error }{s.trades, s.activity, s.positions, s.closedPositions, s.holders, s.openInterest, s.syncStates, s.tailCursors} {
+		if err := c.compact(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the underlying files
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.closeAll()
+}
+
+func (s *FileStore) check(ctx context.Context) error {
+	if s.closed {
+		return fmt.Errorf("store is closed")
+	}
+	return ctx.Err()
+}
+
+func (s *FileStore) closeAll() error {
+	var errs []error
+	for _, c := range []interface{ close() error }{s.trades, s.activity, s.positions, s.closedPositions, s.holders, s.openInterest, s.syncStates, s.tailCursors} {
+		errs = append(errs, c.close())
+	}
+	return errors.Join(errs...)
+}
+
+// fileCollection is an append-only JSONL file with an in-memory index by key
+type fileCollection[T any] struct {
+	path      string
+	file      *os.File
+	key       func(T) string
+	timestamp func(*T) int64
+
+	items []T
+	lines [][]byte
+	index map[string]int
+	stale int
+}
+
+func openFileCollection[T any](dir, name string, key func(T) string, timestamp func(*T) int64) (*fileCollection[T], error) {
+	c := &fileCollection[T]{
+		path:      filepath.Join(dir, name),
+		key:       key,
+		timestamp: timestamp,
+		index:     make(map[string]int),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store file: %w", err)
+	}
+	c.file = f
+	return c, nil
+}
+
+// load replays the file into memory; later lines replace earlier ones with the same key
+func (c *fileCollection[T]) load() error {
+	f, err := os.Open(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open store file: %w", err)
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	var valid int64
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read %s: %w", c.path, err)
+		}
+		if len(line) == 0 {
+			return nil
+		}
+		if line[len(line)-1] != '\n' {
+			// A torn final write from an interrupted process; drop it so
+			// that the next append starts on a fresh line
+			if err := os.Truncate(c.path, valid); err != nil {
+				return fmt.Errorf("failed to repair %s: %w", c.path, err)
+			}
+			return nil
+		}
+		valid += int64(len(line))
+
+		var item T
+		if err := json.Unmarshal(line, &item); err != nil {
+			return fmt.Errorf("failed to decode %s line %d: %w", c.path, lineNo, err)
+		}
+		c.put(item, bytes.TrimSuffix(line, []byte("\n")))
+	}
+}
+
+// put records item in memory and reports whether its key is new
+func (c *fileCollection[T]) put(item T, line []byte) bool {
+	k := c.key(item)
+	if i, ok := c.index[k]; ok {
+		c.items[i] = item
+		c.lines[i] = line
+		c.stale++
+		return false
+	}
+	c.index[k] = len(c.items)
+	c.items = append(c.items, item)
+	c.lines = append(c.lines, line)
+	return true
+}
+
+// upsert appends changed items to the file and returns how many keys were new.
+// Memory is only updated once the write succeeded, so a failed upsert can be retried.
+func (c *fileCollection[T]) upsert(items []T) (int, error) {
+	type pending struct {
+		item T
+		line []byte
+	}
+	var batch []pending
+	inBatch := make(map[string]int) // Key to position in batch
+	for _, item := range items {
+		line, err := json.Marshal(item)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode store record: %w", err)
+		}
+		k := c.key(item)
+		if j, ok := inBatch[k]; ok {
+			if !bytes.Equal(batch[j].line, line) {
+				batch = append(batch, pending{item, line})
+				inBatch[k] = len(batch) - 1
+			}
+			continue
+		}
+		if i, ok := c.index[k]; ok && bytes.Equal(c.lines[i], line) {
+			continue
+		}
+		batch = append(batch, pending{item, line})
+		inBatch[k] = len(batch) - 1
+	}
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	var buf bytes.Buffer
+	for _, p := range batch {
+		buf.Write(p.line)
+		buf.WriteByte('\n')
+	}
+	if err := c.write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, p := range batch {
+		if c.put(p.item, p.line) {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// write appends data to the file. On failure the file is truncated back to
+// its previous size, so that a partial write does not corrupt the next append.
+func (c *fileCollection[T]) write(data []byte) error {
+	if c.file == nil {
+		return fmt.Errorf("failed to write %s: file is closed", c.path)
+	}
+	info, statErr := os.Stat(c.path)
+	if _, err := c.file.Write(data); err != nil {
+		if statErr == nil {
+			os.Truncate(c.path, info.Size())
+		}
+		return fmt.Errorf("failed to write %s: %w", c.path, err)
+	}
+	return nil
+}
+
+// get returns the item stored under key
+func (c *fileCollection[T]) get(key string) (T, bool) {
+	i, ok := c.index[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return c.items[i], true
+}
+
+// query returns matching items sorted by timestamp, latest first if the query is descending
+func (c *fileCollection[T]) query(q *StoreQuery, match func(*T) bool) []T {
+	var out []T
+	for i := range c.items {
+		item := &c.items[i]
+		if q.matchTime(c.timestamp(item)) && match(item) {
+			out = append(out, *item)
+		}
+	}
+	descending := q != nil && q.Descending
+	sort.SliceStable(out, func(i, j int) bool {
+		if descending {
+			return c.timestamp(&out[i]) > c.timestamp(&out[j])
+		}
+		return c.timestamp(&out[i]) < c.timestamp(&out[j])
+	})
+	if q != nil && q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out
+}
+
+// compact rewrites the file with one line per key, in timestamp order
+func (c *fileCollection[T]) compact() error {
+	if c.stale == 0 {
+		return nil
+	}
+
+	order := make([]int, len(c.items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return c.timestamp(&c.items[order[i]]) < c.timestamp(&c.items[order[j]])
+	})
+
+	tmp := c.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create compacted file: %w", err)
+	}
+	bw := bufio.NewWriter(f)
+	for _, i := range order {
+		bw.Write(c.lines[i])
+		bw.WriteByte('\n')
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write compacted file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write compacted file: %w", err)
+	}
+
+	if err := c.file.Close(); err != nil {
+		return fmt.Errorf("failed to close store file: %w", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return fmt.Errorf("failed to replace store file: %w", err)
+	}
+	c.file, err = os.OpenFile(c.path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to reopen store file: %w", err)
+	}
+
+	items := make([]T, len(order))
+	lines := make([][]byte, len(order))
+	for n, i := range order {
+		items[n] = c.items[i]
+		lines[n] = c.lines[i]
+		c.index[c.key(items[n])] = n
+	}
+	c.items, c.lines, c.stale = items, lines, 0
+	return nil
+}
+
+func (c *fileCollection[T]) close() error {
+	if c == nil || c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
@@ -0,0 +1,195 @@
+package polymarketdata
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func openTestStore(t *testing.T, dir string) *FileStore {
+	t.Helper()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func lineCount(t *testing.T, path string) int {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	n := 0
+	for _, b := range data {
+		if b == '\n' {
+			n++
+		}
+	}
+	return n
+}
+
+func TestFileStoreTradesUpsertAndQuery(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := openTestStore(t, dir)
+
+	trades := []Trade{
+		{TransactionHash: "0xa", ProxyWallet: "0x1", Asset: "y", ConditionId: "0xc1", Side: TradeSideBuy, Size: decimal.NewFromInt(5), Price: decimal.RequireFromString("0.4"), Timestamp: 300},
+		{TransactionHash: "0xa", ProxyWallet: "0x1", Asset: "y", ConditionId: "0xc1", Side: TradeSideBuy, Size: decimal.NewFromInt(2), Price: decimal.RequireFromString("0.41"), Timestamp: 300},
+		{TransactionHash: "0xb", ProxyWallet: "0x2", Asset: "n", ConditionId: "0xc2", Side: TradeSideSell, Size: decimal.NewFromInt(1), Price: decimal.RequireFromString("0.6"), Timestamp: 100},
+	}
+	added, err := store.UpsertTrades(ctx, trades)
+	if err != nil || added != 3 {
+		t.Fatalf("UpsertTrades = %d, %v; want 3 new", added, err)
+	}
+	added, err = store.UpsertTrades(ctx, trades)
+	if err != nil || added != 0 {
+		t.Fatalf("repeated UpsertTrades = %d, %v; want 0 new", added, err)
+	}
+	if n := lineCount(t, filepath.Join(dir, "trades.jsonl")); n != 3 {
+		t.Errorf("identical upsert grew the file to %d lines", n)
+	}
+
+	all, _ := store.QueryTrades(ctx, nil)
+	if len(all) != 3 || all[0].Timestamp != 100 {
+		t.Errorf("QueryTrades(nil) = %+v", all)
+	}
+	byUser, _ := store.QueryTrades(ctx, &StoreQuery{User: "0X1"})
+	if len(byUser) != 2 {
+		t.Errorf("expected 2 trades for 0x1, got %d", len(byUser))
+	}
+	byRange, _ := store.QueryTrades(ctx, &StoreQuery{Start: 200, End: 300, ConditionId: "0xc1", Limit: 1})
+	if len(byRange) != 1 || byRange[0].ConditionId != "0xc1" {
+		t.Errorf("range query = %+v", byRange)
+	}
+	latest, _ := store.QueryTrades(ctx, &StoreQuery{Descending: true, Limit: 2})
+	if len(latest) != 2 || latest[0].Timestamp != 300 || latest[1].Timestamp != 300 {
+		t.Errorf("descending query = %+v", latest)
+	}
+	if oldest, _ := store.QueryTrades(ctx, &StoreQuery{User: "0x2", Descending: true, Limit: 1}); len(oldest) != 1 || oldest[0].Timestamp != 100 {
+		t.Errorf("descending query by user = %+v", oldest)
+	}
+}
+
+func TestFileStoreRetriesFailedWrite(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := openTestStore(t, dir)
+	trade := Trade{TransactionHash: "0xa", ProxyWallet: "0x1", Asset: "y", Side: TradeSideBuy, Size: decimal.NewFromInt(5), Price: decimal.RequireFromString("0.4"), Timestamp: 100}
+
+	// Make the next write fail
+	path := filepath.Join(dir, "trades.jsonl")
+	store.trades.file.Close()
+	if _, err := store.UpsertTrades(ctx, []Trade{trade}); err == nil {
+		t.Fatal("expected UpsertTrades to fail on a closed file")
+	}
+	if all, _ := store.QueryTrades(ctx, nil); len(all) != 0 {
+		t.Fatalf("failed write is visible in memory: %+v", all)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	store.trades.file = f
+	added, err := store.UpsertTrades(ctx, []Trade{trade})
+	if err != nil || added != 1 {
+		t.Fatalf("retried UpsertTrades = %d, %v; want 1 new", added, err)
+	}
+	store.Close()
+
+	reopened := openTestStore(t, dir)
+	if all, _ := reopened.QueryTrades(ctx, nil); len(all) != 1 {
+		t.Errorf("expected the retried trade on disk, got %+v", all)
+	}
+}
+
+func TestFileStorePersistsAndCompacts(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := openTestStore(t, dir)
+
+	snap := &PositionsSnapshot{User: "0x1", Timestamp: 10, Positions: []Position{{Asset: "a", Size: decimal.NewFromInt(1)}}}
+	if err := store.SavePositionsSnapshot(ctx, snap); err != nil {
+		t.Fatalf("SavePositionsSnapshot failed: %v", err)
+	}
+	snap.Positions[0].Size = decimal.NewFromInt(2)
+	if err := store.SavePositionsSnapshot(ctx, snap); err != nil {
+		t.Fatalf("SavePositionsSnapshot failed: %v", err)
+	}
+	if err := store.SaveHoldersSnapshot(ctx, &HoldersSnapshot{ConditionId: "0xc", Timestamp: 5}); err != nil {
+		t.Fatalf("SaveHoldersSnapshot failed: %v", err)
+	}
+	readings := []OpenInterestReading{
+		{Timestamp: 1, OpenInterest: OpenInterest{Market: "0xc", Value: decimal.NewFromInt(100)}},
+		{Timestamp: 2, OpenInterest: OpenInterest{Market: "0xc", Value: decimal.NewFromInt(110)}},
+	}
+	if err := store.SaveOpenInterest(ctx, readings); err != nil {
+		t.Fatalf("SaveOpenInterest failed: %v", err)
+	}
+	store.Close()
+
+	positionsPath := filepath.Join(dir, "positions.jsonl")
+	if n := lineCount(t, positionsPath); n != 2 {
+		t.Fatalf("expected 2 lines before compaction, got %d", n)
+	}
+
+	reopened := openTestStore(t, dir)
+	snaps, _ := reopened.QueryPositionsSnapshots(ctx, &StoreQuery{User: "0x1"})
+	if len(snaps) != 1 || !snaps[0].Positions[0].Size.Equal(decimal.NewFromInt(2)) {
+		t.Fatalf("reopened snapshots = %+v", snaps)
+	}
+	if holders, _ := reopened.QueryHoldersSnapshots(ctx, &StoreQuery{ConditionId: "0xc"}); len(holders) != 1 {
+		t.Errorf("expected 1 holders snapshot, got %d", len(holders))
+	}
+	if oi, _ := reopened.QueryOpenInterest(ctx, &StoreQuery{ConditionId: "0xc", Start: 2}); len(oi) != 1 {
+		t.Errorf("expected 1 reading, got %d", len(oi))
+	}
+
+	if err := reopened.Compact(ctx); err != nil {
+		t.Fatalf("Compact failed: %v", err)
+	}
+	if n := lineCount(t, positionsPath); n != 1 {
+		t.Errorf("expected 1 line after compaction, got %d", n)
+	}
+	snap.Timestamp = 20
+	if err := reopened.SavePositionsSnapshot(ctx, snap); err != nil {
+		t.Fatalf("SavePositionsSnapshot after compaction failed: %v", err)
+	}
+	if snaps, _ := reopened.QueryPositionsSnapshots(ctx, nil); len(snaps) != 2 {
+		t.Errorf("expected 2 snapshots, got %d", len(snaps))
+	}
+}
+
+func TestFileStoreRepairsTornWrite(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := openTestStore(t, dir)
+	if _, err := store.UpsertActivity(ctx, []Activity{{TransactionHash: "0xa", Type: ActivityTypeTrade, Timestamp: 1}}); err != nil {
+		t.Fatalf("UpsertActivity failed: %v", err)
+	}
+	store.Close()
+
+	path := filepath.Join(dir, "activity.jsonl")
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	f.WriteString(`{"proxyWallet":"0x`)
+	f.Close()
+
+	reopened := openTestStore(t, dir)
+	if _, err := reopened.UpsertActivity(ctx, []Activity{{TransactionHash: "0xb", Type: ActivityTypeReward, Timestamp: 2}}); err != nil {
+		t.Fatalf("UpsertActivity failed: %v", err)
+	}
+	reopened.Close()
+
+	again := openTestStore(t, dir)
+	activity, err := again.QueryActivity(ctx, nil)
+	if err != nil || len(activity) != 2 {
+		t.Fatalf("QueryActivity = %d records, %v; want 2", len(activity), err)
+	}
+}
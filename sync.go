@@ -0,0 +1,121 @@
+package polymarketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// SyncState records the progress of incremental syncs for a wallet
+type SyncState struct {
+	User              string `json:"user"`
+	LastTimestamp     int64  `json:"lastTimestamp"`       // Checkpoint: newest activity timestamp written to the store
+	LastRunAt         int64  `json:"lastRunAt"`           // Unix seconds when the last run started
+	LastSuccessAt     int64  `json:"lastSuccessAt"`       // Unix seconds when the last run completed without error
+	LastError         string `json:"lastError,omitempty"` // Error of the last run, empty if it succeeded
+	ConsecutiveErrors int    `json:"consecutiveErrors"`   // Number of failed runs since the last success
+	InProgress        bool   `json:"inProgress"`          // True while a run is active; still true after a crash
+}
+
+// SyncResult summarizes a single SyncWallet run
+type SyncResult struct {
+	State           SyncState
+	NewActivity     int  // Activity records that were not in the store before
+	Positions       int  // Positions in the fresh snapshot
+	ClosedPositions int  // Closed positions in the fresh snapshot
+	Resumed         bool // The previous run stopped midway and this run resumed from its checkpoint
+}
+
+// SyncWallet incrementally copies a wallet's data into store. Activity is fetched
+// from the last checkpoint onwards in ascending order and the checkpoint advances
+// after every stored page, so an interrupted run resumes where it stopped.
+// Positions, including dust below the API's default size threshold, and closed
+// positions are stored as fresh snapshots on every run.
+// Writes are idempotent, so re-fetching records at the checkpoint is harmless.
+func (c *Client) SyncWallet(ctx context.Context, user string, store Store) (*SyncResult, error) {
+	if user == "" {
+		return nil, fmt.Errorf("user address is required")
+	}
+
+	state, err := store.GetSyncState(ctx, user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sync state: %w", err)
+	}
+	if state == nil {
+		state = &SyncState{User: user}
+	}
+
+	result := &SyncResult{Resumed: state.InProgress}
+	state.InProgress = true
+	state.LastRunAt = time.Now().Unix()
+	if err := store.SaveSyncState(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to save sync state: %w", err)
+	}
+
+	runErr := c.syncWallet(ctx, user, store, state, result)
+
+	state.InProgress = false
+	if runErr != nil {
+		state.LastError = runErr.Error()
+		state.ConsecutiveErrors++
+	} else {
+		state.LastError = ""
+		state.ConsecutiveErrors = 0
+		state.LastSuccessAt = time.Now().Unix()
+	}
+	// Record the outcome even if the run was cancelled
+	if err := store.SaveSyncState(context.WithoutCancel(ctx), state); err != nil {
+		runErr = errors.Join(runErr, fmt.Errorf("failed to save sync state: %w", err))
+	}
+
+	result.State = *state
+	return result, runErr
+}
+
+func (c *Client) syncWallet(ctx context.Context, user string, store Store, state *SyncState, result *SyncResult) error {
+	err := c.pageActivity(ctx, &GetActivityParams{User: user, Start: state.LastTimestamp}, func(page []Activity) error {
+		added, err := store.UpsertActivity(ctx, page)
+		if err != nil {
+			return fmt.Errorf("failed to store activity: %w", err)
+		}
+		result.NewActivity += added
+
+		if last := page[len(page)-1].Timestamp; last > state.LastTimestamp {
+			state.LastTimestamp = last
+			if err := store.SaveSyncState(ctx, state); err != nil {
+				return fmt.Errorf("failed to save sync state: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync activity: %w", err)
+	}
+
+	now := time.Now().Unix()
+
+	// Snapshot every position, including those below the API's default size threshold of 1
+	zero := decimal.Zero
+	positions, err := c.GetAllPositions(ctx, &GetPositionsParams{User: user, SizeThreshold: &zero})
+	if err != nil {
+		return fmt.Errorf("failed to sync positions: %w", err)
+	}
+	if err := store.SavePositionsSnapshot(ctx, &PositionsSnapshot{User: user, Timestamp: now, Positions: positions}); err != nil {
+		return fmt.Errorf("failed to store positions: %w", err)
+	}
+	result.Positions = len(positions)
+
+	closed, err := c.GetAllClosedPositions(ctx, &GetClosedPositionsParams{User: user})
+	if err != nil {
+		return fmt.Errorf("failed to sync closed positions: %w", err)
+	}
+	if err := store.SaveClosedPositionsSnapshot(ctx, &ClosedPositionsSnapshot{User: user, Timestamp: now, ClosedPositions: closed}); err != nil {
+		return fmt.Errorf("failed to store closed positions: %w", err)
+	}
+	result.ClosedPositions = len(closed)
+
+	return nil
+}
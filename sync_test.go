@@ -0,0 +1,108 @@
+package polymarketdata
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeWalletAPI serves activity, positions and closed positions for a single wallet
+type fakeWalletAPI struct {
+	fakeActivityAPI
+	failPositions bool
+}
+
+func (f *fakeWalletAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/activity":
+		f.serveActivity(w, r)
+	case "/positions":
+		if f.failPositions {
+			http.Error(w, `{"error":"unavailable"}`, http.StatusServiceUnavailable)
+			return
+		}
+		// The API leaves out positions below a size of 1 unless asked otherwise
+		if r.URL.Query().Get("sizeThreshold") == "0" {
+			w.Write([]byte(`[{"asset":"a","size":"3"},{"asset":"d","size":"0.5"}]`))
+			return
+		}
+		w.Write([]byte(`[{"asset":"a","size":"3"}]`))
+	case "/closed-positions":
+		w.Write([]byte(`[{"asset":"b","realizedPnl":"1.5"},{"asset":"c"}]`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestSyncWalletIncremental(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t, t.TempDir())
+	api := &fakeWalletAPI{fakeActivityAPI: fakeActivityAPI{activity: generateActivity(700)}}
+	client := newTestClient(t, api)
+
+	result, err := client.SyncWallet(ctx, "0x1", store)
+	if err != nil {
+		t.Fatalf("SyncWallet failed: %v", err)
+	}
+	if result.NewActivity != 700 || result.Positions != 2 || result.ClosedPositions != 2 {
+		t.Errorf("first run result = %+v", result)
+	}
+	if result.State.LastTimestamp != api.activity[699].Timestamp || result.State.InProgress || result.State.LastSuccessAt == 0 {
+		t.Errorf("first run state = %+v", result.State)
+	}
+
+	// New activity arrives; the next run only transfers the delta
+	api.activity = append(api.activity, generateActivity(703)[700:]...)
+	api.requests = 0
+	result, err = client.SyncWallet(ctx, "0x1", store)
+	if err != nil {
+		t.Fatalf("SyncWallet failed: %v", err)
+	}
+	if result.NewActivity != 3 || api.requests != 1 {
+		t.Errorf("second run stored %d new records in %d requests", result.NewActivity, api.requests)
+	}
+
+	stored, _ := store.QueryActivity(ctx, &StoreQuery{User: "0x1"})
+	if len(stored) != 703 {
+		t.Errorf("store holds %d records, want 703", len(stored))
+	}
+	snaps, _ := store.QueryClosedPositionsSnapshots(ctx, &StoreQuery{User: "0x1"})
+	if len(snaps) == 0 || len(snaps[len(snaps)-1].ClosedPositions) != 2 {
+		t.Errorf("closed positions snapshots = %+v", snaps)
+	}
+}
+
+func TestSyncWalletRecordsErrorsAndResumes(t *testing.T) {
+	ctx := context.Background()
+	store := openTestStore(t, t.TempDir())
+	api := &fakeWalletAPI{fakeActivityAPI: fakeActivityAPI{activity: generateActivity(30)}, failPositions: true}
+	client := newTestClient(t, api)
+
+	result, err := client.SyncWallet(ctx, "0x1", store)
+	if err == nil {
+		t.Fatal("expected positions failure")
+	}
+	state, _ := store.GetSyncState(ctx, "0x1")
+	if state == nil || state.ConsecutiveErrors != 1 || !strings.Contains(state.LastError, "positions") || state.InProgress {
+		t.Fatalf("state after failure = %+v", state)
+	}
+	if state.LastTimestamp != api.activity[29].Timestamp || result.NewActivity != 30 {
+		t.Errorf("activity checkpoint not kept after failure: %+v", state)
+	}
+
+	// Simulate a crash during the next run
+	state.InProgress = true
+	if err := store.SaveSyncState(ctx, state); err != nil {
+		t.Fatalf("SaveSyncState failed: %v", err)
+	}
+
+	api.failPositions = false
+	result, err = client.SyncWallet(ctx, "0x1", store)
+	if err != nil {
+		t.Fatalf("SyncWallet failed: %v", err)
+	}
+	if !result.Resumed || result.NewActivity != 0 || result.State.ConsecutiveErrors != 0 || result.State.LastError != "" {
+		t.Errorf("resumed run result = %+v", result)
+	}
+}
@@ -0,0 +1,329 @@
+package polymarketdata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTailPageSize    = 100
+	defaultTailMinInterval = 2 * time.Second
+	defaultTailMaxInterval = time.Minute
+	defaultTailLookback    = time.Minute
+)
+
+// ErrTailGap is returned when catch-up paging reached the maximum offset
+// before reaching the cursor, so trades between them were missed
+var ErrTailGap = errors.New("trade tail fell behind beyond the maximum offset")
+
+// TailCursor records the progress of a trade tail so that it can resume after a restart
+type TailCursor struct {
+	Key           string           `json:"key"`           // Identifies the tail. Default the sorted condition IDs
+	LastTimestamp int64            `json:"lastTimestamp"` // Newest timestamp of an emitted trade
+	Seen          map[string]int64 `json:"seen"`          // TradeKeys of emitted trades within the lookback of LastTimestamp, to their timestamps
+	UpdatedAt     int64            `json:"updatedAt"`     // Unix seconds of the last save
+}
+
+// TailCursorStore persists tail cursors. FileStore implements it.
+type TailCursorStore interface {
+	// GetTailCursor returns the cursor with the given key, or nil if there is none
+	GetTailCursor(ctx context.Context, key string) (*TailCursor, error)
+	SaveTailCursor(ctx context.Context, cursor *TailCursor) error
+}
+
+// TailTradesOptions configures TailTrades
+type TailTradesOptions struct {
+	PageSize    int             // Optional: Trades per request. Default 100
+	MinInterval time.Duration   // Optional: Shortest time between polls, used while trades keep arriving. Default 2s
+	MaxInterval time.Duration   // Optional: Longest time between polls, reached while the markets are quiet. Default 1m
+	Lookback    time.Duration   // Optional: Unseen trades up to this much older than the newest emitted trade are still emitted, for late indexing. Default 1m
+	TakerOnly   *bool           // Optional: Passed to GetTrades
+	Cursor      TailCursorStore // Optional: The cursor is loaded on start and saved once emitted trades are returned by Poll or delivered by TailTrades
+	CursorKey   string          // Optional: Key of the cursor. Default the sorted, comma-separated condition IDs
+	Buffer      int             // Optional: Capacity of the channel returned by TailTrades
+	OnError     func(error)     // Optional: Called with poll and cursor save errors in TailTrades
+}
+
+// TradeTailer polls the trades of a set of markets and returns each trade once
+type TradeTailer struct {
+	client  *Client
+	markets []string
+	opts    TailTradesOptions
+
+	mu       sync.Mutex
+	cursor   *TailCursor
+	seen     map[string]int64 // TradeKey to timestamp, for trades within the lookback
+	loaded   bool
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewTradeTailer creates a tailer for the given condition IDs
+func (c *Client) NewTradeTailer(markets []string, opts *TailTradesOptions) (*TradeTailer, error) {
+	if len(markets) == 0 {
+		return nil, fmt.Errorf("at least one market is required")
+	}
+	t := &TradeTailer{
+		client:  c,
+		markets: append([]string(nil), markets...),
+		seen:    make(map[string]int64),
+		now:     time.Now,
+	}
+	if opts != nil {
+		t.opts = *opts
+	}
+	if t.opts.PageSize <= 0 {
+		t.opts.PageSize = defaultTailPageSize
+	}
+	if t.opts.PageSize > 10000 {
+		return nil, fmt.Errorf("page size must be at most 10000")
+	}
+	if t.opts.MinInterval <= 0 {
+		t.opts.MinInterval = defaultTailMinInterval
+	}
+	if t.opts.MaxInterval < t.opts.MinInterval {
+		t.opts.MaxInterval = max(defaultTailMaxInterval, t.opts.MinInterval)
+	}
+	if t.opts.Lookback <= 0 {
+		t.opts.Lookback = defaultTailLookback
+	}
+	if t.opts.CursorKey == "" {
+		keys := make([]string, len(markets))
+		for i, m := range markets {
+			keys[i] = strings.ToLower(m)
+		}
+		sort.Strings(keys)
+		t.opts.CursorKey = strings.Join(keys, ",")
+	}
+	t.interval = t.opts.MinInterval
+	return t, nil
+}
+
+// Interval returns the time to wait before the next poll. It halves after a
+// poll that found new trades and doubles after one that found none.
+func (t *TradeTailer) Interval() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.interval
+}
+
+// Cursor returns a copy of the current cursor, or nil before the first poll
+func (t *TradeTailer) Cursor() *TailCursor {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cursor == nil {
+		return nil
+	}
+	c := *t.cursor
+	c.Seen = maps.Clone(c.Seen)
+	return &c
+}
+
+// Poll fetches the latest trades and returns those not returned before, oldest
+// first. Without a cursor the first poll returns the latest page. When a whole
+// page is newer than the cursor, further pages are fetched until the cursor is
+// reached; if that hits the maximum offset the trades found are returned along
+// with ErrTailGap.
+func (t *TradeTailer) Poll(ctx context.Context) ([]Trade, error) {
+	fresh, err := t.collect(ctx)
+	if commitErr := t.commit(ctx, fresh); commitErr != nil {
+		err = errors.Join(err, commitErr)
+	}
+	return fresh, err
+}
+
+// collect fetches the unseen trades and adapts the interval without moving the cursor
+func (t *TradeTailer) collect(ctx context.Context) ([]Trade, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.loaded {
+		if err := t.loadCursor(ctx); err != nil {
+			return nil, err
+		}
+		t.loaded = true
+	}
+
+	fresh, overflowed, err := t.fetch(ctx)
+	if err != nil && !errors.Is(err, ErrTailGap) {
+		t.interval = t.opts.MaxInterval
+		return nil, err
+	}
+
+	switch {
+	case overflowed:
+		t.interval = t.opts.MinInterval
+	case len(fresh) > 0:
+		t.interval = max(t.interval/2, t.opts.MinInterval)
+	default:
+		t.interval = min(t.interval*2, t.opts.MaxInterval)
+	}
+
+	return fresh, err
+}
+
+// commit moves the cursor past emitted trades and saves it
+func (t *TradeTailer) commit(ctx context.Context, trades []Trade) error {
+	if len(trades) == 0 {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.advance(trades)
+	if t.opts.Cursor != nil {
+		if err := t.opts.Cursor.SaveTailCursor(ctx, t.cursor); err != nil {
+			return fmt.Errorf("failed to save tail cursor: %w", err)
+		}
+	}
+	return nil
+}
+
+// fetch pages back from the newest trade until it reaches a trade that was seen
+// or is older than the lookback boundary, and returns the unseen trades oldest first
+func (t *TradeTailer) fetch(ctx context.Context) ([]Trade, bool, error) {
+	params := &GetTradesParams{Market: t.markets, Limit: t.opts.PageSize, TakerOnly: t.opts.TakerOnly}
+	boundary := int64(0)
+	if t.cursor != nil {
+		boundary = t.cursor.LastTimestamp - int64(t.opts.Lookback/time.Second)
+	}
+
+	var fresh []Trade
+	batch := make(map[string]bool) // Pages shift as trades arrive, so a trade may be listed twice
+	overflowed := false
+	for {
+		page, err := t.client.GetTrades(ctx, params)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to fetch trades: %w", err)
+		}
+		// The page reached the cursor if it holds a trade that was seen or is older than the lookback
+		reached := false
+		for _, trade := range page {
+			if t.cursor != nil && trade.Timestamp < boundary {
+				reached = true
+				continue
+			}
+			key := TradeKey(trade)
+			if _, ok := t.seen[key]; ok {
+				reached = true
+				continue
+			}
+			if !batch[key] {
+				batch[key] = true
+				fresh = append(fresh, trade)
+			}
+		}
+
+		// Without a cursor only the latest page is taken
+		if t.cursor == nil || reached || len(page) < params.Limit {
+			break
+		}
+		overflowed = true
+		if params.Offset+params.Limit > 10000 {
+			sortTradesAscending(fresh)
+			return fresh, true, ErrTailGap
+		}
+		params.Offset += params.Limit
+	}
+	sortTradesAscending(fresh)
+	return fresh, overflowed, nil
+}
+
+// advance records emitted trades and drops keys that fell out of the lookback
+func (t *TradeTailer) advance(trades []Trade) {
+	if t.cursor == nil {
+		t.cursor = &TailCursor{Key: t.opts.CursorKey}
+	}
+	for _, trade := range trades {
+		t.seen[TradeKey(trade)] = trade.Timestamp
+		t.cursor.LastTimestamp = max(t.cursor.LastTimestamp, trade.Timestamp)
+	}
+	boundary := t.cursor.LastTimestamp - int64(t.opts.Lookback/time.Second)
+	// A new map, as stores may keep the previous one
+	t.cursor.Seen = make(map[string]int64, len(t.seen))
+	for key, ts := range t.seen {
+		if ts < boundary {
+			delete(t.seen, key)
+			continue
+		}
+		t.cursor.Seen[key] = ts
+	}
+	t.cursor.UpdatedAt = t.now().Unix()
+}
+
+func (t *TradeTailer) loadCursor(ctx context.Context) error {
+	if t.opts.Cursor == nil {
+		return nil
+	}
+	cursor, err := t.opts.Cursor.GetTailCursor(ctx, t.opts.CursorKey)
+	if err != nil {
+		return fmt.Errorf("failed to load tail cursor: %w", err)
+	}
+	if cursor == nil {
+		return nil
+	}
+	t.cursor = cursor
+	maps.Copy(t.seen, cursor.Seen)
+	return nil
+}
+
+// TailTrades polls the trades of the given condition IDs until ctx is cancelled
+// and sends every trade not sent before to the returned channel, oldest first.
+// The channel is closed when ctx is done. The cursor only moves past trades
+// that were sent, so with a cursor store the trades left unsent when ctx is
+// cancelled are sent again after a restart. Poll errors are passed to OnError
+// and the tail retries after the maximum interval.
+func (c *Client) TailTrades(ctx context.Context, markets []string, opts *TailTradesOptions) (<-chan Trade, error) {
+	t, err := c.NewTradeTailer(markets, opts)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan Trade, t.opts.Buffer)
+	go func() {
+		defer close(out)
+		for {
+			trades, err := t.collect(ctx)
+			if err != nil && ctx.Err() == nil && t.opts.OnError != nil {
+				t.opts.OnError(err)
+			}
+			for i, trade := range trades {
+				select {
+				case out <- trade:
+				case <-ctx.Done():
+					// Save the progress of the trades that were sent
+					if err := t.commit(context.WithoutCancel(ctx), trades[:i]); err != nil && t.opts.OnError != nil {
+						t.opts.OnError(err)
+					}
+					return
+				}
+			}
+			if err := t.commit(ctx, trades); err != nil && t.opts.OnError != nil {
+				t.opts.OnError(err)
+			}
+
+			timer := time.NewTimer(t.Interval())
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+	return out, nil
+}
+
+// sortTradesAscending sorts trades listed newest first into oldest first,
+// keeping the reverse API order for equal timestamps
+func sortTradesAscending(trades []Trade) {
+	slices.Reverse(trades)
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Timestamp < trades[j].Timestamp
+	})
+}
@@ -0,0 +1,291 @@
+package polymarketdata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// fakeTradesAPI serves /trades like the real API: newest first, paged by limit and offset
+type fakeTradesAPI struct {
+	mu     sync.Mutex
+	trades []Trade // sorted ascending by timestamp
+}
+
+func (f *fakeTradesAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	q := r.URL.Query()
+	offset, _ := strconv.Atoi(q.Get("offset"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	page := []Trade{}
+	for i := len(f.trades) - 1 - offset; i >= 0 && len(page) < limit; i-- {
+		page = append(page, f.trades[i])
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+// add appends n trades, one per second after the newest
+func (f *fakeTradesAPI) add(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ts := int64(1000)
+	if len(f.trades) > 0 {
+		ts = f.trades[len(f.trades)-1].Timestamp
+	}
+	for i := 0; i < n; i++ {
+		f.trades = append(f.trades, tailTrade(len(f.trades), ts+int64(i)+1))
+	}
+}
+
+func tailTrade(i int, ts int64) Trade {
+	return Trade{
+		ProxyWallet:     "0x1",
+		ConditionId:     "0xc",
+		Asset:           "a",
+		Side:            TradeSideBuy,
+		Timestamp:       ts,
+		TransactionHash: "0x" + strconv.Itoa(i),
+		Size:            decimal.NewFromInt(1),
+		Price:           decimal.RequireFromString("0.5"),
+	}
+}
+
+func TestTradeTailerCatchUpAndInterval(t *testing.T) {
+	api := &fakeTradesAPI{}
+	api.add(30)
+	client := newTestClient(t, api)
+
+	tailer, err := client.NewTradeTailer([]string{"0xc"}, &TailTradesOptions{PageSize: 10, MinInterval: time.Second, MaxInterval: 8 * time.Second})
+	if err != nil {
+		t.Fatalf("NewTradeTailer failed: %v", err)
+	}
+	ctx := context.Background()
+
+	// The first poll takes the latest page only
+	trades, err := tailer.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(trades) != 10 || trades[0].TransactionHash != "0x20" || trades[9].TransactionHash != "0x29" {
+		t.Fatalf("unexpected first poll %+v", trades)
+	}
+
+	// Quiet polls back off
+	for _, want := range []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second} {
+		trades, err = tailer.Poll(ctx)
+		if err != nil {
+			t.Fatalf("Poll failed: %v", err)
+		}
+		if len(trades) != 0 || tailer.Interval() != want {
+			t.Fatalf("got %d trades and interval %s, want none and %s", len(trades), tailer.Interval(), want)
+		}
+	}
+
+	// More trades than a page are caught up across pages, in order and without repeats
+	api.add(25)
+	trades, err = tailer.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(trades) != 25 || tailer.Interval() != time.Second {
+		t.Fatalf("got %d trades and interval %s, want 25 and 1s", len(trades), tailer.Interval())
+	}
+	for i, trade := range trades {
+		if trade.TransactionHash != "0x"+strconv.Itoa(30+i) {
+			t.Fatalf("trade %d is %s", i, trade.TransactionHash)
+		}
+	}
+
+	// A late trade within the lookback is still emitted
+	api.mu.Lock()
+	late := tailTrade(999, api.trades[len(api.trades)-1].Timestamp-5)
+	api.trades = append(api.trades, late)
+	api.mu.Unlock()
+	trades, err = tailer.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(trades) != 1 || trades[0].TransactionHash != "0x999" {
+		t.Errorf("expected the late trade, got %+v", trades)
+	}
+}
+
+func TestTradeTailerResumesFromCursor(t *testing.T) {
+	api := &fakeTradesAPI{}
+	api.add(5)
+	client := newTestClient(t, api)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	store := openTestStore(t, dir)
+	tailer, err := client.NewTradeTailer([]string{"0xc"}, &TailTradesOptions{Cursor: store})
+	if err != nil {
+		t.Fatalf("NewTradeTailer failed: %v", err)
+	}
+	if trades, err := tailer.Poll(ctx); err != nil || len(trades) != 5 {
+		t.Fatalf("Poll = %d trades, %v", len(trades), err)
+	}
+	store.Close()
+
+	api.add(3)
+	store = openTestStore(t, dir)
+	tailer, err = client.NewTradeTailer([]string{"0xC"}, &TailTradesOptions{Cursor: store})
+	if err != nil {
+		t.Fatalf("NewTradeTailer failed: %v", err)
+	}
+	trades, err := tailer.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(trades) != 3 || trades[0].TransactionHash != "0x5" {
+		t.Errorf("expected only the 3 new trades after a restart, got %+v", trades)
+	}
+	if c := tailer.Cursor(); c == nil || c.LastTimestamp != 1008 || c.Key != "0xc" {
+		t.Errorf("unexpected cursor %+v", c)
+	}
+}
+
+func TestTradeTailerGap(t *testing.T) {
+	api := &fakeTradesAPI{}
+	api.add(1)
+	client := newTestClient(t, api)
+	tailer, err := client.NewTradeTailer([]string{"0xc"}, &TailTradesOptions{PageSize: 5000})
+	if err != nil {
+		t.Fatalf("NewTradeTailer failed: %v", err)
+	}
+	if _, err := tailer.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+
+	api.add(16000)
+	trades, err := tailer.Poll(context.Background())
+	if !errors.Is(err, ErrTailGap) {
+		t.Fatalf("expected ErrTailGap, got %v", err)
+	}
+	if len(trades) != 15000 || trades[len(trades)-1].TransactionHash != "0x16000" {
+		t.Errorf("expected the newest 15000 trades, got %d", len(trades))
+	}
+}
+
+func TestTailTrades(t *testing.T) {
+	api := &fakeTradesAPI{}
+	api.add(3)
+	client := newTestClient(t, api)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ch, err := client.TailTrades(ctx, []string{"0xc"}, &TailTradesOptions{MinInterval: 10 * time.Millisecond, MaxInterval: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("TailTrades failed: %v", err)
+	}
+
+	var got []string
+	for trade := range ch {
+		got = append(got, trade.TransactionHash)
+		if len(got) == 3 {
+			api.add(2)
+		}
+		if len(got) == 5 {
+			cancel()
+		}
+	}
+	if len(got) != 5 || got[3] != "0x3" || got[4] != "0x4" {
+		t.Errorf("unexpected trades %v", got)
+	}
+	if _, err := client.TailTrades(ctx, nil, nil); err == nil {
+		t.Error("expected an error without markets")
+	}
+}
+
+func TestTailTradesSavesDeliveredOnly(t *testing.T) {
+	api := &fakeTradesAPI{}
+	api.add(3)
+	client := newTestClient(t, api)
+	dir := t.TempDir()
+
+	store := openTestStore(t, dir)
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := client.TailTrades(ctx, []string{"0xc"}, &TailTradesOptions{Cursor: store})
+	if err != nil {
+		t.Fatalf("TailTrades failed: %v", err)
+	}
+	if trade := <-ch; trade.TransactionHash != "0x0" {
+		t.Fatalf("unexpected trade %+v", trade)
+	}
+	cancel()
+	for range ch {
+	}
+	store.Close()
+
+	store = openTestStore(t, dir)
+	cursor, err := store.GetTailCursor(context.Background(), "0xc")
+	if err != nil {
+		t.Fatalf("GetTailCursor failed: %v", err)
+	}
+	if cursor == nil || cursor.LastTimestamp != 1001 || len(cursor.Seen) != 1 || cursor.Seen[TradeKey(tailTrade(0, 1001))] != 1001 {
+		t.Fatalf("expected the cursor to hold the delivered trade only, got %+v", cursor)
+	}
+
+	// The trades left unsent are emitted after a restart
+	tailer, err := client.NewTradeTailer([]string{"0xc"}, &TailTradesOptions{Cursor: store})
+	if err != nil {
+		t.Fatalf("NewTradeTailer failed: %v", err)
+	}
+	trades, err := tailer.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("Poll failed: %v", err)
+	}
+	if len(trades) != 2 || trades[0].TransactionHash != "0x1" || trades[1].TransactionHash != "0x2" {
+		t.Errorf("expected the 2 unsent trades, got %+v", trades)
+	}
+}
+
+// failingCursorStore has no cursor and fails every save
+type failingCursorStore struct{}
+
+func (failingCursorStore) GetTailCursor(ctx context.Context, key string) (*TailCursor, error) {
+	return nil, nil
+}
+
+func (failingCursorStore) SaveTailCursor(ctx context.Context, cursor *TailCursor) error {
+	return errors.New("disk full")
+}
+
+func TestTailTradesReportsSaveErrorOnCancel(t *testing.T) {
+	api := &fakeTradesAPI{}
+	api.add(3)
+	client := newTestClient(t, api)
+
+	var mu sync.Mutex
+	var errs []error
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := client.TailTrades(ctx, []string{"0xc"}, &TailTradesOptions{
+		Cursor: failingCursorStore{},
+		OnError: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, err)
+		},
+	})
+	if err != nil {
+		t.Fatalf("TailTrades failed: %v", err)
+	}
+	<-ch
+	cancel()
+	for range ch {
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) != 1 {
+		t.Errorf("expected the failed cursor save to be reported, got %v", errs)
+	}
+}
@@ -0,0 +1,244 @@
+// Package taxreport produces per-disposal capital gains and income records
+// from a wallet's activity history, grouped by tax year.
+package taxreport
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/ivanzzeth/polymarket-go-data-client/ledger"
+	"github.com/shopspring/decimal"
+)
+
+// HoldingPeriod classifies how long disposed tokens were held
+type HoldingPeriod string
+
+const (
+	HoldingPeriodShort   HoldingPeriod = "SHORT_TERM"
+	HoldingPeriodLong    HoldingPeriod = "LONG_TERM"
+	HoldingPeriodUnknown HoldingPeriod = "UNKNOWN" // The acquisition is missing from the history
+)
+
+// Options configures a report
+type Options struct {
+	Method        ledger.Method  // Optional: Lot matching method. Default ledger.MethodFIFO
+	Location      *time.Location // Optional: Timezone for tax years and dates. Default UTC
+	LongTermYears int            // Optional: Tokens held longer than this many years are long-term. Default 1
+}
+
+// Disposal is a single disposal matched against a single lot
+type Disposal struct {
+	TaxYear         int
+	ConditionId     string
+	Asset           string
+	OutcomeIndex    int
+	Title           string
+	Type            polymarketdata.ActivityType // TRADE, MERGE, REDEEM or CONVERSION
+	TransactionHash string
+	Acquired        time.Time // Zero if the acquisition is missing from the history
+	Disposed        time.Time
+	Size            decimal.Decimal
+	Proceeds        decimal.Decimal
+	CostBasis       decimal.Decimal
+	Gain            decimal.Decimal
+	HoldingPeriod   HoldingPeriod
+}
+
+// Income is a reward received
+type Income struct {
+	TaxYear         int
+	ConditionId     string
+	Asset           string
+	Title           string
+	TransactionHash string
+	Received        time.Time
+	Amount          decimal.Decimal
+}
+
+// Year holds the records and totals of a single tax year
+type Year struct {
+	Year          int
+	Disposals     []Disposal
+	Income        []Income
+	Proceeds      decimal.Decimal
+	CostBasis     decimal.Decimal
+	ShortTermGain decimal.Decimal // Includes disposals with an unknown holding period
+	LongTermGain  decimal.Decimal
+	TotalIncome   decimal.Decimal
+}
+
+// Report holds the tax years covered by a wallet's history, oldest first
+type Report struct {
+	Years []Year
+}
+
+// Generate fetches a wallet's full activity history and builds a report from it
+func Generate(ctx context.Context, client *polymarketdata.Client, user string, opts *Options) (*Report, error) {
+	activity, err := client.GetAllActivity(ctx, &polymarketdata.GetActivityParams{User: user})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch activity: %w", err)
+	}
+	return Build(activity, opts)
+}
+
+// Build replays activity through a lot-matching ledger and groups the
+// resulting disposals and reward income by tax year. Redemptions are
+// disposals at the settlement price; rewards are income.
+func Build(activity []polymarketdata.Activity, opts *Options) (*Report, error) {
+	method := ledger.MethodFIFO
+	loc := time.UTC
+	longTermYears := 1
+	if opts != nil {
+		if opts.Method != "" {
+			method = opts.Method
+		}
+		if opts.Location != nil {
+			loc = opts.Location
+		}
+		if opts.LongTermYears > 0 {
+			longTermYears = opts.LongTermYears
+		}
+	}
+
+	l, err := ledger.New(method)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.Replay(activity); err != nil {
+		return nil, fmt.Errorf("failed to replay activity: %w", err)
+	}
+
+	titles := make(map[string]string)
+	for _, a := range activity {
+		if a.Title != "" {
+			titles[a.ConditionId] = a.Title
+		}
+	}
+
+	years := make(map[int]*Year)
+	year := func(t time.Time) *Year {
+		y, ok := years[t.Year()]
+		if !ok {
+			y = &Year{Year: t.Year()}
+			years[t.Year()] = y
+		}
+		return y
+	}
+
+	for _, d := range l.Disposals() {
+		disposed := time.Unix(d.DisposedAt, 0).In(loc)
+		rec := Disposal{
+			TaxYear:         disposed.Year(),
+			ConditionId:     d.ConditionId,
+			Asset:           d.Asset,
+			OutcomeIndex:    d.OutcomeIndex,
+			Title:           titles[d.ConditionId],
+			Type:            d.Type,
+			TransactionHash: d.TransactionHash,
+			Disposed:        disposed,
+			Size:            d.Size,
+			Proceeds:        d.Proceeds,
+			CostBasis:       d.CostBasis,
+			Gain:            d.Gain,
+			HoldingPeriod:   HoldingPeriodUnknown,
+		}
+		if !d.Unmatched {
+			rec.Acquired = time.Unix(d.AcquiredAt, 0).In(loc)
+			rec.HoldingPeriod = HoldingPeriodShort
+			if disposed.After(rec.Acquired.AddDate(longTermYears, 0, 0)) {
+				rec.HoldingPeriod = HoldingPeriodLong
+			}
+		}
+
+		y := year(disposed)
+		y.Disposals = append(y.Disposals, rec)
+		y.Proceeds = y.Proceeds.Add(rec.Proceeds)
+		y.CostBasis = y.CostBasis.Add(rec.CostBasis)
+		if rec.HoldingPeriod == HoldingPeriodLong {
+			y.LongTermGain = y.LongTermGain.Add(rec.Gain)
+		} else {
+			y.ShortTermGain = y.ShortTermGain.Add(rec.Gain)
+		}
+	}
+
+	for _, inc := range l.Income() {
+		received := time.Unix(inc.Timestamp, 0).In(loc)
+		y := year(received)
+		y.Income = append(y.Income, Income{
+			TaxYear:         received.Year(),
+			ConditionId:     inc.ConditionId,
+			Asset:           inc.Asset,
+			Title:           titles[inc.ConditionId],
+			TransactionHash: inc.TransactionHash,
+			Received:        received,
+			Amount:          inc.Amount,
+		})
+		y.TotalIncome = y.TotalIncome.Add(inc.Amount)
+	}
+
+	report := &Report{}
+	for _, y := range years {
+		report.Years = append(report.Years, *y)
+	}
+	sort.Slice(report.Years, func(i, j int) bool {
+		return report.Years[i].Year < report.Years[j].Year
+	})
+	return report, nil
+}
+
+// Year returns the records of a single tax year
+func (r *Report) Year(year int) (Year, bool) {
+	for _, y := range r.Years {
+		if y.Year == year {
+			return y, true
+		}
+	}
+	return Year{}, false
+}
+
+var csvHeader = []string{
+	"tax_year", "kind", "condition_id", "asset", "outcome_index", "title", "type", "transaction_hash",
+	"acquired", "disposed", "size", "proceeds", "cost_basis", "gain", "holding_period",
+}
+
+// WriteCSV writes every record grouped by tax year, oldest year first.
+// Dates are formatted as RFC 3339 in the report's timezone. Income rows
+// put the received date in the disposed column and the amount in proceeds and gain.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	for _, y := range r.Years {
+		year := strconv.Itoa(y.Year)
+		for _, d := range y.Disposals {
+			acquired := ""
+			if !d.Acquired.IsZero() {
+				acquired = d.Acquired.Format(time.RFC3339)
+			}
+			cw.Write([]string{
+				year, "disposal", d.ConditionId, d.Asset, strconv.Itoa(d.OutcomeIndex), d.Title, string(d.Type), d.TransactionHash,
+				acquired, d.Disposed.Format(time.RFC3339), d.Size.String(), d.Proceeds.String(), d.CostBasis.String(), d.Gain.String(), string(d.HoldingPeriod),
+			})
+		}
+		for _, inc := range y.Income {
+			cw.Write([]string{
+				year, "income", inc.ConditionId, inc.Asset, "", inc.Title, string(polymarketdata.ActivityTypeReward), inc.TransactionHash,
+				"", inc.Received.Format(time.RFC3339), "", inc.Amount.String(), "0", inc.Amount.String(), "",
+			})
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package taxreport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func d(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func unix(year int, month time.Month, day, hour int) int64 {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC).Unix()
+}
+
+var history = []polymarketdata.Activity{
+	{Timestamp: unix(2023, 3, 1, 12), Type: polymarketdata.ActivityTypeTrade, Side: polymarketdata.TradeSideBuy, ConditionId: "0xa", Asset: "ya", Size: d("100"), UsdcSize: d("40"), MarketRef: polymarketdata.MarketRef{Title: "Market A"}},
+	{Timestamp: unix(2024, 2, 1, 12), Type: polymarketdata.ActivityTypeTrade, Side: polymarketdata.TradeSideBuy, ConditionId: "0xa", Asset: "ya", Size: d("100"), UsdcSize: d("60")},
+	// Sold half at 0.7: FIFO takes the 2023 lot, held for over a year
+	{Timestamp: unix(2024, 6, 1, 12), Type: polymarketdata.ActivityTypeTrade, Side: polymarketdata.TradeSideSell, ConditionId: "0xa", Asset: "ya", Size: d("100"), UsdcSize: d("70")},
+	// Redeemed the rest at 1 on New Year's Eve in UTC, which is 2025 in Tokyo
+	{Timestamp: unix(2024, 12, 31, 20), Type: polymarketdata.ActivityTypeRedeem, ConditionId: "0xa", Size: d("100"), UsdcSize: d("100")},
+	{Timestamp: unix(2024, 7, 1, 0), Type: polymarketdata.ActivityTypeReward, ConditionId: "0xa", UsdcSize: d("3.5")},
+}
+
+func TestBuild(t *testing.T) {
+	report, err := Build(history, nil)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(report.Years) != 1 || report.Years[0].Year != 2024 {
+		t.Fatalf("years = %+v", report.Years)
+	}
+	y := report.Years[0]
+	if len(y.Disposals) != 2 || len(y.Income) != 1 {
+		t.Fatalf("disposals = %d, income = %d", len(y.Disposals), len(y.Income))
+	}
+
+	sale := y.Disposals[0]
+	if sale.HoldingPeriod != HoldingPeriodLong || !sale.Gain.Equal(d("30")) || sale.Title != "Market A" {
+		t.Errorf("sale = %+v", sale)
+	}
+	redeem := y.Disposals[1]
+	if redeem.Type != polymarketdata.ActivityTypeRedeem || redeem.HoldingPeriod != HoldingPeriodShort || !redeem.Gain.Equal(d("40")) {
+		t.Errorf("redemption = %+v", redeem)
+	}
+	if !y.LongTermGain.Equal(d("30")) || !y.ShortTermGain.Equal(d("40")) || !y.TotalIncome.Equal(d("3.5")) {
+		t.Errorf("totals = long %s, short %s, income %s", y.LongTermGain, y.ShortTermGain, y.TotalIncome)
+	}
+}
+
+func TestBuildTimezoneAndMethod(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	report, err := Build(history, &Options{Location: tokyo, Method: "LIFO"})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(report.Years) != 2 {
+		t.Fatalf("expected the redemption to move to 2025, got %+v", report.Years)
+	}
+	y2025, ok := report.Year(2025)
+	if !ok || len(y2025.Disposals) != 1 || y2025.Disposals[0].Disposed.Location() != tokyo {
+		t.Errorf("2025 = %+v", y2025)
+	}
+	y2024, _ := report.Year(2024)
+	// LIFO sells the 2024 lot first: short-term gain of 10
+	if y2024.Disposals[0].HoldingPeriod != HoldingPeriodShort || !y2024.Disposals[0].Gain.Equal(d("10")) {
+		t.Errorf("LIFO sale = %+v", y2024.Disposals[0])
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	report, err := Build(history, nil)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("expected header and 3 rows, got %d", len(rows))
+	}
+	if rows[1][0] != "2024" || rows[1][1] != "disposal" || rows[1][8] != "2023-03-01T12:00:00Z" || rows[1][14] != "LONG_TERM" {
+		t.Errorf("disposal row = %v", rows[1])
+	}
+	if rows[3][1] != "income" || rows[3][11] != "3.5" {
+		t.Errorf("income row = %v", rows[3])
+	}
+}
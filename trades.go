@@ -101,6 +101,8 @@ func (c *Client) GetTrades(ctx context.Context, params *GetTradesParams) ([]Trad
 		return nil, fmt.Errorf("failed to decode trades response: %w", err)
 	}
 
+	c.Directory().ObserveTrades(trades)
+
 	return trades, nil
 }
 
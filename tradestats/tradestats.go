@@ -0,0 +1,298 @@
+// Package tradestats computes VWAP, TWAP, volume, trade rate and realized
+// volatility over trades, per outcome token. Statistics are never mixed
+// across assets: the YES and NO tokens of a market trade at complementary
+// prices, so combining them into a single VWAP is meaningless.
+//
+// Volatility uses absolute price changes rather than log returns, because
+// outcome prices are probabilities that can approach zero.
+package tradestats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// precision is the number of decimal places kept by divisions and square roots
+const precision = 16
+
+// Stats summarizes the trades of a single asset within a window
+type Stats struct {
+	Asset         string
+	ConditionId   string
+	OutcomeIndex  int
+	Outcome       string
+	Start         time.Time
+	End           time.Time
+	Trades        int
+	Volume        decimal.Decimal // Tokens traded
+	BuyVolume     decimal.Decimal
+	SellVolume    decimal.Decimal
+	Notional      decimal.Decimal // Cash traded: sum of Size × Price
+	VWAP          decimal.Decimal // Notional / Volume
+	TWAP          decimal.Decimal // Price averaged over time, each trade's price held until the next trade
+	AvgTradeSize  decimal.Decimal // Volume / Trades
+	TradesPerHour decimal.Decimal
+	Volatility    decimal.Decimal // Realized volatility: square root of the summed squared price changes between trades
+	FirstPrice    decimal.Decimal
+	LastPrice     decimal.Decimal
+}
+
+// Compute returns statistics per asset for trades in [start, end). A zero start or end
+// leaves that side of the window open and uses the first or last trade instead.
+// The last trade before start, if given, sets the price at the start of the window
+// for the TWAP; otherwise the TWAP starts at the first trade in the window.
+func Compute(trades []polymarketdata.Trade, start, end time.Time) map[string]Stats {
+	byAsset := make(map[string][]polymarketdata.Trade)
+	for _, t := range trades {
+		byAsset[t.Asset] = append(byAsset[t.Asset], t)
+	}
+
+	out := make(map[string]Stats, len(byAsset))
+	for asset, ts := range byAsset {
+		sortTrades(ts)
+
+		var prior *polymarketdata.Trade
+		var window []polymarketdata.Trade
+		for i := range ts {
+			switch {
+			case !start.IsZero() && ts[i].Timestamp < start.Unix():
+				prior = &ts[i]
+			case !end.IsZero() && ts[i].Timestamp >= end.Unix():
+			default:
+				window = append(window, ts[i])
+			}
+		}
+		if len(window) == 0 {
+			continue
+		}
+
+		from, to := start, end
+		if from.IsZero() {
+			from = time.Unix(window[0].Timestamp, 0)
+		}
+		if to.IsZero() {
+			to = time.Unix(window[len(window)-1].Timestamp, 0)
+		}
+		out[asset] = summarize(window, prior, from, to)
+	}
+	return out
+}
+
+// ComputeAsset returns statistics for a single asset, ignoring trades of other assets
+func ComputeAsset(trades []polymarketdata.Trade, asset string, start, end time.Time) (Stats, bool) {
+	s, ok := Compute(filterAsset(trades, asset), start, end)[asset]
+	return s, ok
+}
+
+// Rolling maintains statistics over a sliding time window as trades arrive.
+// Trades may arrive out of order; trades older than the window are evicted.
+// A Rolling is not safe for concurrent use.
+type Rolling struct {
+	window time.Duration
+	assets map[string]*rollingAsset
+}
+
+type rollingAsset struct {
+	trades []polymarketdata.Trade // Sorted by timestamp
+	prior  *polymarketdata.Trade  // Newest evicted trade, sets the TWAP price at the window start
+	latest int64
+}
+
+// NewRolling creates a rolling calculator over the given window
+func NewRolling(window time.Duration) (*Rolling, error) {
+	if window < time.Second {
+		return nil, fmt.Errorf("window must be at least one second")
+	}
+	return &Rolling{
+		window: window,
+		assets: make(map[string]*rollingAsset),
+	}, nil
+}
+
+// Window returns the length of the sliding window
+func (r *Rolling) Window() time.Duration {
+	return r.window
+}
+
+// Add adds a trade. The window of its asset ends at the newest trade seen.
+func (r *Rolling) Add(t polymarketdata.Trade) {
+	a, ok := r.assets[t.Asset]
+	if !ok {
+		a = &rollingAsset{}
+		r.assets[t.Asset] = a
+	}
+
+	cutoff := a.latest - int64(r.window/time.Second)
+	if a.latest != 0 && t.Timestamp <= cutoff {
+		// Already outside the window, but may still be the price at its start
+		if a.prior == nil || t.Timestamp >= a.prior.Timestamp {
+			a.prior = &t
+		}
+		return
+	}
+
+	i := sort.Search(len(a.trades), func(i int) bool { return a.trades[i].Timestamp > t.Timestamp })
+	a.trades = append(a.trades, polymarketdata.Trade{})
+	copy(a.trades[i+1:], a.trades[i:])
+	a.trades[i] = t
+
+	if t.Timestamp > a.latest {
+		a.latest = t.Timestamp
+		r.evict(a, a.latest)
+	}
+}
+
+// AddAll adds a slice of trades
+func (r *Rolling) AddAll(trades []polymarketdata.Trade) {
+	for _, t := range trades {
+		r.Add(t)
+	}
+}
+
+// Advance moves the window of every asset forward to end at now, evicting
+// trades that fall out of it. Windows never move backwards.
+func (r *Rolling) Advance(now time.Time) {
+	for _, a := range r.assets {
+		if now.Unix() > a.latest {
+			a.latest = now.Unix()
+			r.evict(a, a.latest)
+		}
+	}
+}
+
+// Assets returns the assets with trades in their current window, sorted
+func (r *Rolling) Assets() []string {
+	var out []string
+	for asset, a := range r.assets {
+		if len(a.trades) > 0 {
+			out = append(out, asset)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Stats returns the statistics of an asset over its current window
+func (r *Rolling) Stats(asset string) (Stats, bool) {
+	a, ok := r.assets[asset]
+	if !ok || len(a.trades) == 0 {
+		return Stats{}, false
+	}
+	end := time.Unix(a.latest, 0)
+	return summarize(a.trades, a.prior, end.Add(-r.window), end), true
+}
+
+// evict drops trades at or before latest - window. The window is (latest - window, latest].
+func (r *Rolling) evict(a *rollingAsset, latest int64) {
+	cutoff := latest - int64(r.window/time.Second)
+	n := sort.Search(len(a.trades), func(i int) bool { return a.trades[i].Timestamp > cutoff })
+	if n == 0 {
+		return
+	}
+	prior := a.trades[n-1]
+	a.prior = &prior
+	a.trades = append(a.trades[:0], a.trades[n:]...)
+}
+
+// summarize computes statistics over trades sorted by timestamp, all of the same asset
+func summarize(trades []polymarketdata.Trade, prior *polymarketdata.Trade, start, end time.Time) Stats {
+	first, last := trades[0], trades[len(trades)-1]
+	s := Stats{
+		Asset:        first.Asset,
+		ConditionId:  first.ConditionId,
+		OutcomeIndex: first.OutcomeIndex,
+		Outcome:      first.Outcome,
+		Start:        start,
+		End:          end,
+		Trades:       len(trades),
+		FirstPrice:   first.Price,
+		LastPrice:    last.Price,
+	}
+
+	var squares decimal.Decimal
+	for i, t := range trades {
+		s.Volume = s.Volume.Add(t.Size)
+		s.Notional = s.Notional.Add(t.Size.Mul(t.Price))
+		switch t.Side {
+		case polymarketdata.TradeSideBuy:
+			s.BuyVolume = s.BuyVolume.Add(t.Size)
+		case polymarketdata.TradeSideSell:
+			s.SellVolume = s.SellVolume.Add(t.Size)
+		}
+		if i > 0 {
+			d := t.Price.Sub(trades[i-1].Price)
+			squares = squares.Add(d.Mul(d))
+		}
+	}
+
+	n := decimal.NewFromInt(int64(len(trades)))
+	s.AvgTradeSize = s.Volume.DivRound(n, precision)
+	if s.Volume.IsPositive() {
+		s.VWAP = s.Notional.DivRound(s.Volume, precision)
+	}
+	if hours := end.Sub(start).Hours(); hours > 0 {
+		s.TradesPerHour = n.DivRound(decimal.NewFromFloat(hours), precision)
+	}
+	s.Volatility = sqrt(squares)
+	s.TWAP = twap(trades, prior, start.Unix(), end.Unix())
+	return s
+}
+
+// twap integrates the step function of trade prices over [start, end]. Each
+// price holds until the next trade; the last one holds until end.
+func twap(trades []polymarketdata.Trade, prior *polymarketdata.Trade, start, end int64) decimal.Decimal {
+	from := trades[0].Timestamp
+	price := trades[0].Price
+	if prior != nil && start < from {
+		from, price = start, prior.Price
+	}
+	if end <= from {
+		return trades[len(trades)-1].Price
+	}
+
+	var area decimal.Decimal
+	at := from
+	for _, t := range trades {
+		if t.Timestamp > at {
+			area = area.Add(price.Mul(decimal.NewFromInt(t.Timestamp - at)))
+			at = t.Timestamp
+		}
+		price = t.Price
+	}
+	if end > at {
+		area = area.Add(price.Mul(decimal.NewFromInt(end - at)))
+	}
+	return area.DivRound(decimal.NewFromInt(end-from), precision)
+}
+
+// sqrt returns the square root of a non-negative decimal
+func sqrt(d decimal.Decimal) decimal.Decimal {
+	if !d.IsPositive() {
+		return decimal.Zero
+	}
+	r, err := d.PowWithPrecision(decimal.NewFromFloat(0.5), precision)
+	if err != nil {
+		return decimal.Zero
+	}
+	return r
+}
+
+func sortTrades(trades []polymarketdata.Trade) {
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Timestamp < trades[j].Timestamp
+	})
+}
+
+func filterAsset(trades []polymarketdata.Trade, asset string) []polymarketdata.Trade {
+	var out []polymarketdata.Trade
+	for _, t := range trades {
+		if t.Asset == asset {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+package tradestats
+
+import (
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func trade(asset string, ts int64, side polymarketdata.TradeSide, size, price string) polymarketdata.Trade {
+	return polymarketdata.Trade{
+		Side:      side,
+		Asset:     asset,
+		Size:      decimal.RequireFromString(size),
+		Price:     decimal.RequireFromString(price),
+		Timestamp: ts,
+	}
+}
+
+var fixture = []polymarketdata.Trade{
+	trade("yes", 90, polymarketdata.TradeSideBuy, "20", "0.5"),
+	trade("yes", 0, polymarketdata.TradeSideBuy, "10", "0.4"),
+	trade("no", 30, polymarketdata.TradeSideBuy, "100", "0.5"),
+	trade("yes", 60, polymarketdata.TradeSideSell, "10", "0.6"),
+}
+
+func expectDecimal(t *testing.T, name string, got decimal.Decimal, want string) {
+	t.Helper()
+	if !got.Round(8).Equal(decimal.RequireFromString(want)) {
+		t.Errorf("%s: got %s, want %s", name, got, want)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	stats := Compute(fixture, time.Time{}, time.Time{})
+	if len(stats) != 2 {
+		t.Fatalf("expected stats for 2 assets, got %d", len(stats))
+	}
+
+	s := stats["yes"]
+	if s.Trades != 3 {
+		t.Errorf("expected 3 trades, got %d", s.Trades)
+	}
+	expectDecimal(t, "volume", s.Volume, "40")
+	expectDecimal(t, "buy volume", s.BuyVolume, "30")
+	expectDecimal(t, "sell volume", s.SellVolume, "10")
+	expectDecimal(t, "notional", s.Notional, "20")
+	expectDecimal(t, "vwap", s.VWAP, "0.5")
+	expectDecimal(t, "twap", s.TWAP, "0.46666667")
+	expectDecimal(t, "avg trade size", s.AvgTradeSize, "13.33333333")
+	expectDecimal(t, "trades per hour", s.TradesPerHour, "120")
+	expectDecimal(t, "volatility", s.Volatility, "0.2236068")
+	expectDecimal(t, "first price", s.FirstPrice, "0.4")
+	expectDecimal(t, "last price", s.LastPrice, "0.5")
+
+	// The NO token is not mixed into the YES statistics
+	expectDecimal(t, "no vwap", stats["no"].VWAP, "0.5")
+	expectDecimal(t, "no volume", stats["no"].Volume, "100")
+}
+
+func TestComputeWindow(t *testing.T) {
+	s, ok := ComputeAsset(fixture, "yes", time.Unix(30, 0), time.Unix(120, 0))
+	if !ok {
+		t.Fatal("expected stats")
+	}
+	if s.Trades != 2 {
+		t.Errorf("expected 2 trades in window, got %d", s.Trades)
+	}
+	// The trade before the window sets the price at its start
+	expectDecimal(t, "twap", s.TWAP, "0.5")
+	expectDecimal(t, "vwap", s.VWAP, "0.53333333")
+
+	if _, ok := ComputeAsset(fixture, "yes", time.Unix(200, 0), time.Time{}); ok {
+		t.Error("expected no stats for an empty window")
+	}
+}
+
+func TestRolling(t *testing.T) {
+	r, err := NewRolling(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.AddAll([]polymarketdata.Trade{
+		trade("yes", 0, polymarketdata.TradeSideBuy, "10", "0.4"),
+		trade("yes", 60, polymarketdata.TradeSideSell, "10", "0.6"),
+		trade("yes", 90, polymarketdata.TradeSideBuy, "20", "0.5"),
+	})
+
+	s, ok := r.Stats("yes")
+	if !ok {
+		t.Fatal("expected stats")
+	}
+	if s.Trades != 2 || s.Start.Unix() != 30 || s.End.Unix() != 90 {
+		t.Errorf("unexpected window: %d trades, %d - %d", s.Trades, s.Start.Unix(), s.End.Unix())
+	}
+	expectDecimal(t, "twap", s.TWAP, "0.5")
+	expectDecimal(t, "volume", s.Volume, "30")
+
+	// A late trade outside the window only moves the starting price
+	r.Add(trade("yes", 10, polymarketdata.TradeSideBuy, "1", "0.3"))
+	s, _ = r.Stats("yes")
+	if s.Trades != 2 {
+		t.Errorf("expected late trade to be excluded, got %d trades", s.Trades)
+	}
+	expectDecimal(t, "twap", s.TWAP, "0.45")
+
+	// A late trade inside the window is inserted in order
+	r.Add(trade("yes", 80, polymarketdata.TradeSideBuy, "5", "0.7"))
+	s, _ = r.Stats("yes")
+	if s.Trades != 3 {
+		t.Errorf("expected 3 trades, got %d", s.Trades)
+	}
+	expectDecimal(t, "volume", s.Volume, "35")
+	expectDecimal(t, "volatility", s.Volatility, "0.2236068")
+
+	r.Advance(time.Unix(200, 0))
+	if _, ok := r.Stats("yes"); ok {
+		t.Error("expected the window to be empty after advancing")
+	}
+	if len(r.Assets()) != 0 {
+		t.Errorf("expected no active assets, got %v", r.Assets())
+	}
+}
+
+func TestNewRollingInvalidWindow(t *testing.T) {
+	if _, err := NewRolling(0); err == nil {
+		t.Error("expected an error for a zero window")
+	}
+}
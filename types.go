@@ -35,6 +35,32 @@ const (
 	SortDirectionDesc SortDirection = "DESC"
 )
 
+// UserProfile holds the public profile fields attached to trades, activity and holders
+type UserProfile struct {
+	Name                  string `json:"name"`
+	Pseudonym             string `json:"pseudonym"`
+	Bio                   string `json:"bio"`
+	ProfileImage          string `json:"profileImage"`
+	ProfileImageOptimized string `json:"profileImageOptimized"`
+}
+
+// DisplayName returns the name, falling back to the pseudonym
+func (p UserProfile) DisplayName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.Pseudonym
+}
+
+// MarketRef holds the market metadata attached to positions, trades and activity
+type MarketRef struct {
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	Icon      string `json:"icon"`
+	EventSlug string `json:"eventSlug"`
+	Outcome   string `json:"outcome"`
+}
+
 // Position represents a user's position in a market
 type Position struct {
 	ProxyWallet        string          `json:"proxyWallet"`
@@ -52,16 +78,12 @@ type Position struct {
 	CurPrice           decimal.Decimal `json:"curPrice"`
 	Redeemable         bool            `json:"redeemable"`
 	Mergeable          bool            `json:"mergeable"`
-	Title              string          `json:"title"`
-	Slug               string          `json:"slug"`
-	Icon               string          `json:"icon"`
-	EventSlug          string          `json:"eventSlug"`
-	Outcome            string          `json:"outcome"`
-	OutcomeIndex       int             `json:"outcomeIndex"`
-	OppositeOutcome    string          `json:"oppositeOutcome"`
-	OppositeAsset      string          `json:"oppositeAsset"`
-	EndDate            string          `json:"endDate"`
-	NegativeRisk       bool            `json:"negativeRisk"`
+	MarketRef
+	OutcomeIndex    int    `json:"outcomeIndex"`
+	OppositeOutcome string `json:"oppositeOutcome"`
+	OppositeAsset   string `json:"oppositeAsset"`
+	EndDate         string `json:"endDate"`
+	NegativeRisk    bool   `json:"negativeRisk"`
 }
 
 // GetPositionsParams represents parameters for getting user positions
@@ -97,25 +119,17 @@ const (
 
 // Trade represents a trade record
 type Trade struct {
-	ProxyWallet           string          `json:"proxyWallet"` // User Profile Address (0x-prefixed, 40 hex chars). Example: "0x56687bf447db6ffa42ffe2204a05edaa20f55839"
-	Side                  TradeSide       `json:"side"`        // Available options: BUY, SELL
-	Asset                 string          `json:"asset"`
-	ConditionId           string          `json:"conditionId"` // 0x-prefixed 64-hex string. Example: "0xdd22472e552920b8438158ea7238bfadfa4f736aa4cee91a6b86c39ead110917"
-	Size                  decimal.Decimal `json:"size"`
-	Price                 decimal.Decimal `json:"price"`
-	Timestamp             int64           `json:"timestamp"`
-	Title                 string          `json:"title"`
-	Slug                  string          `json:"slug"`
-	Icon                  string          `json:"icon"`
-	EventSlug             string          `json:"eventSlug"`
-	Outcome               string          `json:"outcome"`
-	OutcomeIndex          int             `json:"outcomeIndex"`
-	Name                  string          `json:"name"`
-	Pseudonym             string          `json:"pseudonym"`
-	Bio                   string          `json:"bio"`
-	ProfileImage          string          `json:"profileImage"`
-	ProfileImageOptimized string          `json:"profileImageOptimized"`
-	TransactionHash       string          `json:"transactionHash"`
+	ProxyWallet string          `json:"proxyWallet"` // User Profile Address (0x-prefixed, 40 hex chars). Example: "0x56687bf447db6ffa42ffe2204a05edaa20f55839"
+	Side        TradeSide       `json:"side"`        // Available options: BUY, SELL
+	Asset       string          `json:"asset"`
+	ConditionId string          `json:"conditionId"` // 0x-prefixed 64-hex string. Example: "0xdd22472e552920b8438158ea7238bfadfa4f736aa4cee91a6b86c39ead110917"
+	Size        decimal.Decimal `json:"size"`
+	Price       decimal.Decimal `json:"price"`
+	Timestamp   int64           `json:"timestamp"`
+	MarketRef
+	OutcomeIndex int `json:"outcomeIndex"`
+	UserProfile
+	TransactionHash string `json:"transactionHash"`
 }
 
 // GetTradesParams represents parameters for getting trades
@@ -165,27 +179,19 @@ const (
 
 // Activity represents a user's on-chain activity
 type Activity struct {
-	ProxyWallet           string          `json:"proxyWallet"` // User Profile Address (0x-prefixed, 40 hex chars). Example: "0x56687bf447db6ffa42ffe2204a05edaa20f55839"
-	Timestamp             int64           `json:"timestamp"`
-	ConditionId           string          `json:"conditionId"` // 0x-prefixed 64-hex string. Example: "0xdd22472e552920b8438158ea7238bfadfa4f736aa4cee91a6b86c39ead110917"
-	Type                  ActivityType    `json:"type"`        // Available options: TRADE, SPLIT, MERGE, REDEEM, REWARD, CONVERSION
-	Size                  decimal.Decimal `json:"size"`
-	UsdcSize              decimal.Decimal `json:"usdcSize"`
-	TransactionHash       string          `json:"transactionHash"`
-	Price                 decimal.Decimal `json:"price"`
-	Asset                 string          `json:"asset"`
-	Side                  TradeSide       `json:"side"` // Available options: BUY, SELL
-	OutcomeIndex          int             `json:"outcomeIndex"`
-	Title                 string          `json:"title"`
-	Slug                  string          `json:"slug"`
-	Icon                  string          `json:"icon"`
-	EventSlug             string          `json:"eventSlug"`
-	Outcome               string          `json:"outcome"`
-	Name                  string          `json:"name"`
-	Pseudonym             string          `json:"pseudonym"`
-	Bio                   string          `json:"bio"`
-	ProfileImage          string          `json:"profileImage"`
-	ProfileImageOptimized string          `json:"profileImageOptimized"`
+	ProxyWallet     string          `json:"proxyWallet"` // User Profile Address (0x-prefixed, 40 hex chars). Example: "0x56687bf447db6ffa42ffe2204a05edaa20f55839"
+	Timestamp       int64           `json:"timestamp"`
+	ConditionId     string          `json:"conditionId"` // 0x-prefixed 64-hex string. Example: "0xdd22472e552920b8438158ea7238bfadfa4f736aa4cee91a6b86c39ead110917"
+	Type            ActivityType    `json:"type"`        // Available options: TRADE, SPLIT, MERGE, REDEEM, REWARD, CONVERSION
+	Size            decimal.Decimal `json:"size"`
+	UsdcSize        decimal.Decimal `json:"usdcSize"`
+	TransactionHash string          `json:"transactionHash"`
+	Price           decimal.Decimal `json:"price"`
+	Asset           string          `json:"asset"`
+	Side            TradeSide       `json:"side"` // Available options: BUY, SELL
+	OutcomeIndex    int             `json:"outcomeIndex"`
+	MarketRef
+	UserProfile
 }
 
 // GetActivityParams represents parameters for getting user activity
@@ -206,15 +212,11 @@ type GetActivityParams struct {
 // Holder represents a holder of a market token
 type Holder struct {
 	ProxyWallet           string          `json:"proxyWallet"`           // User Profile Address (0x-prefixed, 40 hex chars). Example: "0x56687bf447db6ffa42ffe2204a05edaa20f55839"
-	Bio                   string          `json:"bio"`                   // User bio
 	Asset                 string          `json:"asset"`                 // Asset address
-	Pseudonym             string          `json:"pseudonym"`             // User pseudonym
 	Amount                decimal.Decimal `json:"amount"`                // Amount held
 	DisplayUsernamePublic bool            `json:"displayUsernamePublic"` // Whether username is public
 	OutcomeIndex          int             `json:"outcomeIndex"`          // Outcome index
-	Name                  string          `json:"name"`                  // User name
-	ProfileImage          string          `json:"profileImage"`          // Profile image URL
-	ProfileImageOptimized string          `json:"profileImageOptimized"` // Optimized profile image URL
+	UserProfile
 }
 
 // MarketHolders represents holders for a specific market token
@@ -254,22 +256,18 @@ const (
 
 // ClosedPosition represents a closed position for a user
 type ClosedPosition struct {
-	ProxyWallet     string          `json:"proxyWallet"` // User Profile Address (0x-prefixed, 40 hex chars). Example: "0x56687bf447db6ffa42ffe2204a05edaa20f55839"
-	Asset           string          `json:"asset"`
-	ConditionId     string          `json:"conditionId"` // 0x-prefixed 64-hex string. Example: "0xdd22472e552920b8438158ea7238bfadfa4f736aa4cee91a6b86c39ead110917"
-	AvgPrice        decimal.Decimal `json:"avgPrice"`
-	TotalBought     decimal.Decimal `json:"totalBought"`
-	RealizedPnl     decimal.Decimal `json:"realizedPnl"`
-	CurPrice        decimal.Decimal `json:"curPrice"`
-	Title           string          `json:"title"`
-	Slug            string          `json:"slug"`
-	Icon            string          `json:"icon"`
-	EventSlug       string          `json:"eventSlug"`
-	Outcome         string          `json:"outcome"`
-	OutcomeIndex    int             `json:"outcomeIndex"`
-	OppositeOutcome string          `json:"oppositeOutcome"`
-	OppositeAsset   string          `json:"oppositeAsset"`
-	EndDate         string          `json:"endDate"`
+	ProxyWallet string          `json:"proxyWallet"` // User Profile Address (0x-prefixed, 40 hex chars). Example: "0x56687bf447db6ffa42ffe2204a05edaa20f55839"
+	Asset       string          `json:"asset"`
+	ConditionId string          `json:"conditionId"` // 0x-prefixed 64-hex string. Example: "0xdd22472e552920b8438158ea7238bfadfa4f736aa4cee91a6b86c39ead110917"
+	AvgPrice    decimal.Decimal `json:"avgPrice"`
+	TotalBought decimal.Decimal `json:"totalBought"`
+	RealizedPnl decimal.Decimal `json:"realizedPnl"`
+	CurPrice    decimal.Decimal `json:"curPrice"`
+	MarketRef
+	OutcomeIndex    int    `json:"outcomeIndex"`
+	OppositeOutcome string `json:"oppositeOutcome"`
+	OppositeAsset   string `json:"oppositeAsset"`
+	EndDate         string `json:"endDate"`
 }
 
 // GetClosedPositionsParams represents parameters for getting closed positions
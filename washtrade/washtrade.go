@@ -0,0 +1,514 @@
+// Package washtrade flags trading patterns that suggest wash trading or
+// self-dealing, and discounts the volume they account for.
+//
+// Three patterns are detected per asset:
+//   - SELF_TRADE: a wallet buys and sells within a short window at
+//     near-identical prices
+//   - CYCLE: tokens pass from wallet to wallet within a small cluster and
+//     return to where they started
+//   - ZERO_NET: a wallet trades repeatedly within a window but its position
+//     barely changes
+//
+// Trades carry no counterparty, so a transfer from one wallet to another is
+// inferred from a sell and a buy of the same size at the same price and time,
+// preferring fills that share a transaction hash. The heuristics produce
+// false positives, notably for market makers, and alerts should be reviewed.
+package washtrade
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// precision is the number of decimal places kept by divisions
+const precision = 16
+
+// Pattern names a suspicious pattern
+type Pattern string
+
+const (
+	PatternSelfTrade Pattern = "SELF_TRADE"
+	PatternCycle     Pattern = "CYCLE"
+	PatternZeroNet   Pattern = "ZERO_NET"
+)
+
+// Options configures the heuristics
+type Options struct {
+	Window         time.Duration   // Optional: Maximum time between the legs of a self-trade or a transfer. Default 10 minutes
+	CycleWindow    time.Duration   // Optional: Maximum duration of a cycle. Default 1 hour
+	PriceTolerance decimal.Decimal // Optional: Maximum price difference between matched legs. Default 0.01
+	SizeTolerance  decimal.Decimal // Optional: Maximum relative size difference between the hops of a transfer or cycle. Default 0.01
+	MaxClusterSize int             // Optional: Maximum number of wallets in a cycle. Default 3
+	NetTolerance   decimal.Decimal // Optional: Maximum net change, as a share of volume, for ZERO_NET. Default 0.05
+	MinTrades      int             // Optional: Minimum trades by a wallet in an asset for ZERO_NET. Default 4
+	NetWindow      time.Duration   // Optional: Maximum time spanned by the trades netted for ZERO_NET. Default 24 hours
+	MinScore       decimal.Decimal // Optional: Alerts scoring lower are dropped
+}
+
+// Alert is a detected pattern with the trades that make it up
+type Alert struct {
+	Pattern     Pattern
+	Score       decimal.Decimal // Share of the involved wallets' volume in the asset that the pattern accounts for, between 0 and 1
+	Asset       string
+	ConditionId string
+	Title       string
+	Wallets     []string        // Involved wallets, sorted
+	Volume      decimal.Decimal // Tokens traded in the evidence
+	Notional    decimal.Decimal // Cash traded in the evidence
+	Start       int64           // Timestamp of the first evidence trade
+	End         int64           // Timestamp of the last evidence trade
+	Reason      string
+	Trades      []polymarketdata.Trade // Evidence, in timestamp order
+}
+
+// Report holds the alerts and the volume they account for
+type Report struct {
+	Alerts          []Alert // Highest score first
+	TotalVolume     decimal.Decimal
+	TotalNotional   decimal.Decimal
+	SuspectVolume   decimal.Decimal // Tokens traded in trades that are evidence in any alert
+	SuspectNotional decimal.Decimal
+	SuspectShare    decimal.Decimal // SuspectNotional / TotalNotional
+
+	suspect map[string]bool // By TradeKey
+}
+
+// IsSuspect reports whether a trade is evidence in any alert
+func (r *Report) IsSuspect(t polymarketdata.Trade) bool {
+	return r.suspect[polymarketdata.TradeKey(t)]
+}
+
+// Clean returns the trades that are not evidence in any alert, to discount
+// suspicious volume before computing volume-based signals
+func (r *Report) Clean(trades []polymarketdata.Trade) []polymarketdata.Trade {
+	out := make([]polymarketdata.Trade, 0, len(trades))
+	for _, t := range trades {
+		if !r.IsSuspect(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+type config struct {
+	window         int64
+	cycleWindow    int64
+	priceTolerance decimal.Decimal
+	sizeTolerance  decimal.Decimal
+	maxClusterSize int
+	netTolerance   decimal.Decimal
+	minTrades      int
+	netWindow      int64
+	minScore       decimal.Decimal
+}
+
+// Analyze runs every heuristic over trades
+func Analyze(trades []polymarketdata.Trade, opts *Options) (*Report, error) {
+	cfg := config{
+		window:         int64((10 * time.Minute) / time.Second),
+		cycleWindow:    int64(time.Hour / time.Second),
+		priceTolerance: decimal.RequireFromString("0.01"),
+		sizeTolerance:  decimal.RequireFromString("0.01"),
+		maxClusterSize: 3,
+		netTolerance:   decimal.RequireFromString("0.05"),
+		minTrades:      4,
+		netWindow:      int64((24 * time.Hour) / time.Second),
+	}
+	if opts != nil {
+		if opts.Window > 0 {
+			cfg.window = int64(opts.Window / time.Second)
+		}
+		if opts.CycleWindow > 0 {
+			cfg.cycleWindow = int64(opts.CycleWindow / time.Second)
+		}
+		if opts.PriceTolerance.IsPositive() {
+			cfg.priceTolerance = opts.PriceTolerance
+		}
+		if opts.SizeTolerance.IsPositive() {
+			cfg.sizeTolerance = opts.SizeTolerance
+		}
+		if opts.MaxClusterSize > 0 {
+			cfg.maxClusterSize = opts.MaxClusterSize
+		}
+		if opts.NetTolerance.IsPositive() {
+			cfg.netTolerance = opts.NetTolerance
+		}
+		if opts.MinTrades > 0 {
+			cfg.minTrades = opts.MinTrades
+		}
+		if opts.NetWindow > 0 {
+			cfg.netWindow = int64(opts.NetWindow / time.Second)
+		}
+		cfg.minScore = opts.MinScore
+	}
+	if cfg.maxClusterSize < 2 {
+		return nil, fmt.Errorf("max cluster size must be at least 2")
+	}
+
+	byAsset := make(map[string][]polymarketdata.Trade)
+	var assets []string
+	report := &Report{suspect: make(map[string]bool)}
+	for _, t := range trades {
+		if _, ok := byAsset[t.Asset]; !ok {
+			assets = append(assets, t.Asset)
+		}
+		byAsset[t.Asset] = append(byAsset[t.Asset], t)
+		report.TotalVolume = report.TotalVolume.Add(t.Size)
+		report.TotalNotional = report.TotalNotional.Add(t.Size.Mul(t.Price))
+	}
+
+	for _, asset := range assets {
+		a := newAssetTrades(byAsset[asset])
+		alerts := a.selfTrades(cfg)
+		alerts = append(alerts, a.cycles(cfg)...)
+		alerts = append(alerts, a.zeroNet(cfg)...)
+		for _, alert := range alerts {
+			if alert.Score.LessThan(cfg.minScore) {
+				continue
+			}
+			report.Alerts = append(report.Alerts, alert)
+		}
+	}
+
+	sort.SliceStable(report.Alerts, func(i, j int) bool {
+		return report.Alerts[i].Score.GreaterThan(report.Alerts[j].Score)
+	})
+	for _, alert := range report.Alerts {
+		for _, t := range alert.Trades {
+			key := polymarketdata.TradeKey(t)
+			if report.suspect[key] {
+				continue
+			}
+			report.suspect[key] = true
+			report.SuspectVolume = report.SuspectVolume.Add(t.Size)
+			report.SuspectNotional = report.SuspectNotional.Add(t.Size.Mul(t.Price))
+		}
+	}
+	if report.TotalNotional.IsPositive() {
+		report.SuspectShare = report.SuspectNotional.DivRound(report.TotalNotional, precision)
+	}
+	return report, nil
+}
+
+// assetTrades holds the trades of a single asset in timestamp order
+type assetTrades struct {
+	trades []polymarketdata.Trade
+	volume map[string]decimal.Decimal // Tokens traded by lowercase wallet
+}
+
+func newAssetTrades(trades []polymarketdata.Trade) *assetTrades {
+	a := &assetTrades{trades: trades, volume: make(map[string]decimal.Decimal)}
+	sort.SliceStable(a.trades, func(i, j int) bool { return a.trades[i].Timestamp < a.trades[j].Timestamp })
+	for _, t := range a.trades {
+		w := strings.ToLower(t.ProxyWallet)
+		a.volume[w] = a.volume[w].Add(t.Size)
+	}
+	return a
+}
+
+// alert builds an alert from evidence trade indexes, scored against the wallets' volume
+func (a *assetTrades) alert(pattern Pattern, evidence []int, reason string) Alert {
+	sort.Ints(evidence)
+	first := a.trades[evidence[0]]
+	alert := Alert{
+		Pattern:     pattern,
+		Asset:       first.Asset,
+		ConditionId: first.ConditionId,
+		Title:       first.Title,
+		Start:       first.Timestamp,
+		End:         a.trades[evidence[len(evidence)-1]].Timestamp,
+		Reason:      reason,
+	}
+	wallets := make(map[string]bool)
+	for _, i := range evidence {
+		t := a.trades[i]
+		alert.Trades = append(alert.Trades, t)
+		alert.Volume = alert.Volume.Add(t.Size)
+		alert.Notional = alert.Notional.Add(t.Size.Mul(t.Price))
+		wallets[strings.ToLower(t.ProxyWallet)] = true
+	}
+
+	var total decimal.Decimal
+	for w := range wallets {
+		alert.Wallets = append(alert.Wallets, w)
+		total = total.Add(a.volume[w])
+	}
+	sort.Strings(alert.Wallets)
+	if total.IsPositive() {
+		alert.Score = decimal.Min(alert.Volume.DivRound(total, precision), decimal.NewFromInt(1))
+	}
+	return alert
+}
+
+// selfTrades pairs each wallet's buys and sells that are close in time and price
+func (a *assetTrades) selfTrades(cfg config) []Alert {
+	byWallet := make(map[string][]int)
+	var wallets []string
+	for i, t := range a.trades {
+		w := strings.ToLower(t.ProxyWallet)
+		if _, ok := byWallet[w]; !ok {
+			wallets = append(wallets, w)
+		}
+		byWallet[w] = append(byWallet[w], i)
+	}
+
+	var alerts []Alert
+	for _, w := range wallets {
+		idx := byWallet[w]
+		remaining := make(map[int]decimal.Decimal, len(idx))
+		for _, i := range idx {
+			remaining[i] = a.trades[i].Size
+		}
+
+		evidence := make(map[int]bool)
+		var matched decimal.Decimal
+		for _, si := range idx {
+			sell := a.trades[si]
+			if sell.Side != polymarketdata.TradeSideSell {
+				continue
+			}
+			// Trades are in timestamp order, so only those within the window are visited
+			from := sort.Search(len(idx), func(k int) bool { return a.trades[idx[k]].Timestamp >= sell.Timestamp-cfg.window })
+			for _, bi := range idx[from:] {
+				buy := a.trades[bi]
+				if buy.Timestamp-sell.Timestamp > cfg.window {
+					break
+				}
+				if buy.Side != polymarketdata.TradeSideBuy || !remaining[bi].IsPositive() {
+					continue
+				}
+				if buy.Price.Sub(sell.Price).Abs().GreaterThan(cfg.priceTolerance) {
+					continue
+				}
+				m := decimal.Min(remaining[si], remaining[bi])
+				remaining[si] = remaining[si].Sub(m)
+				remaining[bi] = remaining[bi].Sub(m)
+				matched = matched.Add(m)
+				evidence[si], evidence[bi] = true, true
+				if !remaining[si].IsPositive() {
+					break
+				}
+			}
+		}
+		if len(evidence) == 0 {
+			continue
+		}
+
+		var ev []int
+		for i := range evidence {
+			ev = append(ev, i)
+		}
+		reason := fmt.Sprintf("bought and sold %s tokens within %ds at prices within %s", matched, cfg.window, cfg.priceTolerance)
+		alert := a.alert(PatternSelfTrade, ev, reason)
+		// Only the matched part of the evidence is suspicious
+		if v := a.volume[w]; v.IsPositive() {
+			alert.Score = decimal.Min(matched.Mul(decimal.NewFromInt(2)).DivRound(v, precision), decimal.NewFromInt(1))
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// transfer is a sell by one wallet matched with a buy by another
+type transfer struct {
+	from, to  string
+	sell, buy int
+	size      decimal.Decimal
+	at        int64
+}
+
+// transfers infers token movements between different wallets
+func (a *assetTrades) transfers(cfg config) []transfer {
+	used := make(map[int]bool)
+	var out []transfer
+	for si, sell := range a.trades {
+		if sell.Side != polymarketdata.TradeSideSell {
+			continue
+		}
+		best := -1
+		from := sort.Search(len(a.trades), func(k int) bool { return a.trades[k].Timestamp >= sell.Timestamp-cfg.window })
+		for bi := from; bi < len(a.trades); bi++ {
+			buy := a.trades[bi]
+			if buy.Timestamp-sell.Timestamp > cfg.window {
+				break
+			}
+			if used[bi] || buy.Side != polymarketdata.TradeSideBuy || strings.EqualFold(buy.ProxyWallet, sell.ProxyWallet) {
+				continue
+			}
+			if buy.Price.Sub(sell.Price).Abs().GreaterThan(cfg.priceTolerance) {
+				continue
+			}
+			if !sameSize(buy.Size, sell.Size, cfg.sizeTolerance) {
+				continue
+			}
+			if best == -1 || (sell.TransactionHash != "" && strings.EqualFold(buy.TransactionHash, sell.TransactionHash)) {
+				best = bi
+				if strings.EqualFold(buy.TransactionHash, sell.TransactionHash) {
+					break
+				}
+			}
+		}
+		if best == -1 {
+			continue
+		}
+		used[best] = true
+		buy := a.trades[best]
+		at := sell.Timestamp
+		if buy.Timestamp > at {
+			at = buy.Timestamp
+		}
+		out = append(out, transfer{
+			from: strings.ToLower(sell.ProxyWallet),
+			to:   strings.ToLower(buy.ProxyWallet),
+			sell: si,
+			buy:  best,
+			size: sell.Size,
+			at:   at,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].at < out[j].at })
+	return out
+}
+
+// cycles finds chains of transfers of the same size that return to the first seller
+func (a *assetTrades) cycles(cfg config) []Alert {
+	transfers := a.transfers(cfg)
+	var alerts []Alert
+	seen := make(map[string]bool)
+
+	var path []int
+	var search func(start, last int, visited map[string]bool)
+	search = func(start, last int, visited map[string]bool) {
+		first, prev := transfers[start], transfers[last]
+		for next := last + 1; next < len(transfers); next++ {
+			t := transfers[next]
+			if t.at-first.at > cfg.cycleWindow {
+				return
+			}
+			if t.from != prev.to || !sameSize(t.size, first.size, cfg.sizeTolerance) {
+				continue
+			}
+			path = append(path, next)
+			switch {
+			case t.to == first.from:
+				a.addCycle(transfers, path, seen, &alerts)
+			case !visited[t.to] && len(path) < cfg.maxClusterSize:
+				visited[t.to] = true
+				search(start, next, visited)
+				delete(visited, t.to)
+			}
+			path = path[:len(path)-1]
+		}
+	}
+
+	for start := range transfers {
+		path = []int{start}
+		search(start, start, map[string]bool{transfers[start].from: true, transfers[start].to: true})
+	}
+	return alerts
+}
+
+func (a *assetTrades) addCycle(transfers []transfer, path []int, seen map[string]bool, alerts *[]Alert) {
+	var keys []string
+	var evidence []int
+	hops := make([]string, 0, len(path)+1)
+	for _, p := range path {
+		t := transfers[p]
+		keys = append(keys, fmt.Sprint(t.sell, ">", t.buy))
+		evidence = append(evidence, t.sell, t.buy)
+		hops = append(hops, t.from)
+	}
+	hops = append(hops, transfers[path[0]].from)
+	sort.Strings(keys)
+	key := strings.Join(keys, ",")
+	if seen[key] {
+		return
+	}
+	seen[key] = true
+
+	reason := fmt.Sprintf("%s tokens passed %s", transfers[path[0]].size, strings.Join(hops, " -> "))
+	*alerts = append(*alerts, a.alert(PatternCycle, evidence, reason))
+}
+
+// zeroNet flags wallets that trade often within the net window without
+// changing their position. A window slides over each wallet's trades, so a
+// buy and a much later sell do not net out.
+func (a *assetTrades) zeroNet(cfg config) []Alert {
+	byWallet := make(map[string][]int)
+	var wallets []string
+	for i, t := range a.trades {
+		w := strings.ToLower(t.ProxyWallet)
+		if _, ok := byWallet[w]; !ok {
+			wallets = append(wallets, w)
+		}
+		byWallet[w] = append(byWallet[w], i)
+	}
+
+	var alerts []Alert
+	for _, w := range wallets {
+		idx := byWallet[w]
+		if len(idx) < cfg.minTrades {
+			continue
+		}
+
+		evidence := make(map[int]bool)
+		var net, volume decimal.Decimal
+		var best struct {
+			score, net, volume decimal.Decimal
+			trades             int
+		}
+		lo := 0
+		for hi, i := range idx {
+			net, volume = net.Add(signedSize(a.trades[i])), volume.Add(a.trades[i].Size)
+			for a.trades[i].Timestamp-a.trades[idx[lo]].Timestamp > cfg.netWindow {
+				net, volume = net.Sub(signedSize(a.trades[idx[lo]])), volume.Sub(a.trades[idx[lo]].Size)
+				lo++
+			}
+			n := hi - lo + 1
+			if n < cfg.minTrades || !volume.IsPositive() || net.Abs().GreaterThan(volume.Mul(cfg.netTolerance)) {
+				continue
+			}
+			for _, j := range idx[lo : hi+1] {
+				evidence[j] = true
+			}
+			score := decimal.NewFromInt(1).Sub(net.Abs().DivRound(volume, precision))
+			if best.trades == 0 || score.GreaterThan(best.score) || (score.Equal(best.score) && n > best.trades) {
+				best.score, best.net, best.volume, best.trades = score, net, volume, n
+			}
+		}
+		if len(evidence) == 0 {
+			continue
+		}
+
+		var ev []int
+		for i := range evidence {
+			ev = append(ev, i)
+		}
+		reason := fmt.Sprintf("traded %s tokens in %d trades within %ds for a net change of %s", best.volume, best.trades, cfg.netWindow, best.net)
+		alert := a.alert(PatternZeroNet, ev, reason)
+		alert.Score = best.score
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// signedSize is the change a trade makes to the wallet's position
+func signedSize(t polymarketdata.Trade) decimal.Decimal {
+	switch t.Side {
+	case polymarketdata.TradeSideBuy:
+		return t.Size
+	case polymarketdata.TradeSideSell:
+		return t.Size.Neg()
+	}
+	return decimal.Zero
+}
+
+// sameSize reports whether two sizes differ by at most tolerance relative to the larger
+func sameSize(a, b, tolerance decimal.Decimal) bool {
+	return a.Sub(b).Abs().LessThanOrEqual(decimal.Max(a, b).Mul(tolerance))
+}
@@ -0,0 +1,188 @@
+package washtrade
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func trade(wallet, asset, tx string, side polymarketdata.TradeSide, ts int64, size, price string) polymarketdata.Trade {
+	return polymarketdata.Trade{
+		ProxyWallet:     wallet,
+		Asset:           asset,
+		ConditionId:     "0xc",
+		TransactionHash: tx,
+		Side:            side,
+		Timestamp:       ts,
+		Size:            decimal.RequireFromString(size),
+		Price:           decimal.RequireFromString(price),
+	}
+}
+
+const (
+	buy  = polymarketdata.TradeSideBuy
+	sell = polymarketdata.TradeSideSell
+)
+
+func alertsOf(r *Report, p Pattern) []Alert {
+	var out []Alert
+	for _, a := range r.Alerts {
+		if a.Pattern == p {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func TestSelfTrade(t *testing.T) {
+	report, err := Analyze([]polymarketdata.Trade{
+		trade("0xw", "yes", "0x1", buy, 0, "100", "0.50"),
+		trade("0xw", "yes", "0x2", sell, 60, "100", "0.505"),
+		trade("0xw", "yes", "0x3", sell, 5000, "50", "0.70"),
+		trade("0xn", "yes", "0x4", buy, 10, "100", "0.60"),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alerts := alertsOf(report, PatternSelfTrade)
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 self-trade alert, got %+v", report.Alerts)
+	}
+	a := alerts[0]
+	if len(a.Trades) != 2 || len(a.Wallets) != 1 || a.Wallets[0] != "0xw" {
+		t.Errorf("unexpected evidence %+v", a)
+	}
+	// 200 of the wallet's 250 tokens traded were a round trip
+	if !a.Score.Equal(decimal.RequireFromString("0.8")) {
+		t.Errorf("expected score 0.8, got %s", a.Score)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	trades := []polymarketdata.Trade{
+		trade("0xA", "yes", "0x1", sell, 0, "50", "0.4"),
+		trade("0xb", "yes", "0x1", buy, 0, "50", "0.4"),
+		trade("0xb", "yes", "0x2", sell, 700, "50", "0.4"),
+		trade("0xc", "yes", "0x2", buy, 700, "50", "0.4"),
+		trade("0xc", "yes", "0x3", sell, 1400, "50", "0.4"),
+		trade("0xa", "yes", "0x3", buy, 1400, "50", "0.4"),
+		// An unrelated transfer to an outside wallet
+		trade("0xd", "yes", "0x4", sell, 800, "20", "0.4"),
+		trade("0xe", "yes", "0x4", buy, 800, "20", "0.4"),
+	}
+	report, err := Analyze(trades, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Alerts) != 1 || report.Alerts[0].Pattern != PatternCycle {
+		t.Fatalf("expected a single cycle alert, got %+v", report.Alerts)
+	}
+	a := report.Alerts[0]
+	if len(a.Trades) != 6 || len(a.Wallets) != 3 || !a.Score.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("unexpected cycle alert %+v", a)
+	}
+	if a.Start != 0 || a.End != 1400 {
+		t.Errorf("unexpected cycle span %d - %d", a.Start, a.End)
+	}
+
+	// Clusters larger than the limit are not reported
+	report, err = Analyze(trades, &Options{MaxClusterSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alertsOf(report, PatternCycle)) != 0 {
+		t.Errorf("expected no cycles of at most 2 wallets, got %+v", report.Alerts)
+	}
+}
+
+func TestZeroNetAndClean(t *testing.T) {
+	trades := []polymarketdata.Trade{
+		trade("0xz", "yes", "0x1", buy, 0, "10", "0.3"),
+		trade("0xz", "yes", "0x2", sell, 3600, "10", "0.5"),
+		trade("0xz", "yes", "0x3", buy, 7200, "10", "0.4"),
+		trade("0xz", "yes", "0x4", sell, 10800, "10", "0.6"),
+		trade("0xn", "yes", "0x5", buy, 100, "60", "0.5"),
+	}
+	report, err := Analyze(trades, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alerts := alertsOf(report, PatternZeroNet)
+	if len(alerts) != 1 || alerts[0].Wallets[0] != "0xz" || !alerts[0].Score.Equal(decimal.NewFromInt(1)) {
+		t.Fatalf("expected a zero-net alert for 0xz, got %+v", report.Alerts)
+	}
+
+	if !report.SuspectVolume.Equal(decimal.NewFromInt(40)) || !report.TotalVolume.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("unexpected volumes %s of %s", report.SuspectVolume, report.TotalVolume)
+	}
+	// 18 of 48 cash traded is suspect
+	if !report.SuspectShare.Equal(decimal.RequireFromString("0.375")) {
+		t.Errorf("unexpected suspect share %s", report.SuspectShare)
+	}
+	clean := report.Clean(trades)
+	if len(clean) != 1 || clean[0].ProxyWallet != "0xn" {
+		t.Errorf("expected only the honest trade to remain, got %+v", clean)
+	}
+
+	report, err = Analyze(trades, &Options{MinTrades: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Alerts) != 0 {
+		t.Errorf("expected no alerts below MinTrades, got %+v", report.Alerts)
+	}
+}
+
+func TestZeroNetWindow(t *testing.T) {
+	day := int64(24 * 3600)
+	trades := []polymarketdata.Trade{
+		trade("0xz", "yes", "0x1", buy, 0, "10", "0.3"),
+		trade("0xz", "yes", "0x2", buy, 60, "10", "0.3"),
+		trade("0xz", "yes", "0x3", sell, 10*day, "10", "0.5"),
+		trade("0xz", "yes", "0x4", sell, 10*day+60, "10", "0.5"),
+	}
+	report, err := Analyze(trades, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Alerts) != 0 || len(report.Clean(trades)) != 4 {
+		t.Errorf("expected a spread-out round trip not to be flagged, got %+v", report.Alerts)
+	}
+
+	// Four round trips an hour apart only net out within a window that spans them
+	var churn []polymarketdata.Trade
+	for i := int64(0); i < 4; i++ {
+		side := buy
+		if i%2 == 1 {
+			side = sell
+		}
+		churn = append(churn, trade("0xz", "yes", fmt.Sprint(i), side, i*3600, "10", "0.5"))
+	}
+	if report, _ = Analyze(churn, &Options{NetWindow: 2 * time.Hour}); len(report.Alerts) != 0 {
+		t.Errorf("expected no alerts within a 2h window, got %+v", report.Alerts)
+	}
+	if report, _ = Analyze(churn, &Options{NetWindow: 3 * time.Hour}); len(alertsOf(report, PatternZeroNet)) != 1 {
+		t.Errorf("expected a zero-net alert within a 3h window, got %+v", report.Alerts)
+	}
+}
+
+func TestMinScore(t *testing.T) {
+	trades := []polymarketdata.Trade{
+		trade("0xw", "yes", "0x1", buy, 0, "10", "0.50"),
+		trade("0xw", "yes", "0x2", sell, 60, "10", "0.50"),
+		trade("0xw", "yes", "0x3", buy, 9000, "80", "0.70"),
+	}
+	report, err := Analyze(trades, &Options{MinScore: decimal.RequireFromString("0.5")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Alerts) != 0 {
+		t.Errorf("expected the low-score alert to be dropped, got %+v", report.Alerts)
+	}
+}
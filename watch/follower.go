@@ -0,0 +1,364 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// TradeSignal is a trade by a followed wallet, normalized for copy trading
+type TradeSignal struct {
+	ProxyWallet     string
+	Name            string // Display name of the wallet, if known
+	ConditionId     string
+	Title           string
+	Asset           string
+	OutcomeIndex    int
+	Outcome         string
+	Side            polymarketdata.TradeSide
+	Size            decimal.Decimal // Tokens traded
+	Price           decimal.Decimal
+	UsdcSize        decimal.Decimal // Cash traded
+	TransactionHash string
+	Timestamp       int64 // Time of the trade (Unix seconds)
+	// Position is the wallet's holding of the asset right after the trade: its
+	// current size less the later trades in the same poll. Positions are fetched
+	// after the trades, so trades the positions endpoint has not indexed yet are
+	// not reflected.
+	Position      decimal.Decimal
+	PositionKnown bool          // Positions could be fetched
+	DetectedAt    time.Time     // Time of the poll that found the trade
+	Lag           time.Duration // DetectedAt less the trade time
+	Activity      polymarketdata.Activity
+
+	wallet     string       // The followed wallet, lowercase
+	checkpoint followCursor // The wallet's checkpoint once this signal is delivered
+}
+
+// LagStats describes how far behind a followed wallet's signals were detected
+type LagStats struct {
+	Signals  int
+	Last     time.Duration
+	Mean     time.Duration
+	Max      time.Duration
+	LastPoll time.Time // Time of the last successful poll of the wallet
+}
+
+// FollowerOptions configures a Follower
+type FollowerOptions struct {
+	Since       time.Time            // Optional: Trades before this are not reported. Default the time of the first poll
+	Checkpoints map[string]time.Time // Optional: Per-wallet start overriding Since, e.g. a saved Checkpoint. Trades at that exact time are reported again
+	MinSize     decimal.Decimal      // Optional: Ignore trades of fewer tokens
+	MinUsdcSize decimal.Decimal      // Optional: Ignore trades of less cash
+	Interval    time.Duration        // Optional: Time between polls in Run. Default DefaultInterval
+	OnError     func(error)          // Optional: Called with poll errors in Run
+}
+
+// Follower polls the activity of followed wallets and reports their new trades
+// as signals. A wallet's checkpoint only moves past a trade once its signal was
+// returned by Poll or delivered by Run, so signals that Run could not deliver
+// are found again by the next poll. Checkpoints live in memory; pass them back
+// through FollowerOptions.Checkpoints to resume after a restart.
+type Follower struct {
+	client  *polymarketdata.Client
+	wallets []string
+	opts    FollowerOptions
+
+	mu      sync.Mutex
+	started bool
+	cursors map[string]followCursor // By lowercase wallet
+	lag     map[string]*LagStats
+	total   map[string]time.Duration // Sum of lags, for the mean
+	now     func() time.Time
+}
+
+// followCursor is the checkpoint of a wallet: the newest timestamp handled
+// and the keys of the activity at that timestamp, which the next poll fetches again
+type followCursor struct {
+	start int64
+	seen  map[string]bool
+}
+
+func (c followCursor) clone() followCursor {
+	seen := make(map[string]bool, len(c.seen))
+	for k := range c.seen {
+		seen[k] = true
+	}
+	return followCursor{start: c.start, seen: seen}
+}
+
+// handled reports whether the cursor has already moved past a
+func (c followCursor) handled(a polymarketdata.Activity) bool {
+	return a.Timestamp < c.start || (a.Timestamp == c.start && c.seen[polymarketdata.ActivityKey(a)])
+}
+
+// advance moves the cursor past a
+func (c *followCursor) advance(a polymarketdata.Activity) {
+	if a.Timestamp > c.start {
+		c.start = a.Timestamp
+		c.seen = make(map[string]bool)
+	}
+	c.seen[polymarketdata.ActivityKey(a)] = true
+}
+
+// NewFollower creates a follower for the given wallets
+func NewFollower(client *polymarketdata.Client, wallets []string, opts *FollowerOptions) (*Follower, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	if len(wallets) == 0 {
+		return nil, fmt.Errorf("at least one wallet is required")
+	}
+	f := &Follower{
+		client:  client,
+		cursors: make(map[string]followCursor),
+		lag:     make(map[string]*LagStats),
+		total:   make(map[string]time.Duration),
+		now:     time.Now,
+	}
+	for _, w := range wallets {
+		f.wallets = append(f.wallets, strings.ToLower(w))
+	}
+	if opts != nil {
+		f.opts = *opts
+	}
+	return f, nil
+}
+
+// Poll fetches every wallet's trades since its checkpoint and returns the new
+// ones as signals in timestamp order. A failure for one wallet does not stop
+// the others; all failures are returned joined.
+func (f *Follower) Poll(ctx context.Context) ([]TradeSignal, error) {
+	signals, final, err := f.collect(ctx)
+	for _, s := range signals {
+		f.commit(s)
+	}
+	f.commitFinal(final)
+	return signals, err
+}
+
+// collect fetches the new signals without moving the checkpoints. Each signal
+// carries the checkpoint to move to once it is delivered; final holds the
+// checkpoints to move to once all of them are, which also skips filtered trades.
+func (f *Follower) collect(ctx context.Context) ([]TradeSignal, map[string]followCursor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now()
+	if !f.started {
+		since := f.opts.Since
+		if since.IsZero() {
+			since = now
+		}
+		checkpoints := make(map[string]time.Time)
+		for w, t := range f.opts.Checkpoints {
+			checkpoints[strings.ToLower(w)] = t
+		}
+		for _, w := range f.wallets {
+			start := since
+			if t, ok := checkpoints[w]; ok {
+				start = t
+			}
+			f.cursors[w] = followCursor{start: start.Unix(), seen: make(map[string]bool)}
+		}
+		f.started = true
+	}
+
+	var signals []TradeSignal
+	final := make(map[string]followCursor)
+	var errs []error
+	for _, wallet := range f.wallets {
+		found, cursor, err := f.pollWallet(ctx, wallet, now)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if cursor != nil {
+			final[wallet] = *cursor
+		}
+		signals = append(signals, found...)
+	}
+
+	sort.SliceStable(signals, func(i, j int) bool {
+		if signals[i].Timestamp != signals[j].Timestamp {
+			return signals[i].Timestamp < signals[j].Timestamp
+		}
+		return signals[i].ProxyWallet < signals[j].ProxyWallet
+	})
+	return signals, final, errors.Join(errs...)
+}
+
+// commit moves a wallet's checkpoint past a delivered signal and records its lag
+func (f *Follower) commit(s TradeSignal) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cursors[s.wallet] = s.checkpoint
+
+	stats := f.stats(s.wallet)
+	stats.Signals++
+	stats.Last = s.Lag
+	stats.Max = max(stats.Max, s.Lag)
+	f.total[s.wallet] += s.Lag
+	stats.Mean = f.total[s.wallet] / time.Duration(stats.Signals)
+}
+
+func (f *Follower) commitFinal(final map[string]followCursor) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for wallet, cursor := range final {
+		f.cursors[wallet] = cursor
+	}
+}
+
+func (f *Follower) stats(wallet string) *LagStats {
+	stats := f.lag[wallet]
+	if stats == nil {
+		stats = &LagStats{}
+		f.lag[wallet] = stats
+	}
+	return stats
+}
+
+// pollWallet returns the new signals of a wallet and its checkpoint after all of them,
+// or a nil checkpoint if the activity could not be fetched
+func (f *Follower) pollWallet(ctx context.Context, wallet string, now time.Time) ([]TradeSignal, *followCursor, error) {
+	cursor := f.cursors[wallet].clone()
+	activity, err := f.client.GetAllActivity(ctx, &polymarketdata.GetActivityParams{
+		User:  wallet,
+		Type:  []polymarketdata.ActivityType{polymarketdata.ActivityTypeTrade},
+		Start: cursor.start,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch activity of %s: %w", wallet, err)
+	}
+	f.stats(wallet).LastPoll = now
+
+	var signals []TradeSignal
+	for _, a := range activity {
+		if a.Type != polymarketdata.ActivityTypeTrade || cursor.handled(a) {
+			continue
+		}
+		cursor.advance(a)
+		s := newTradeSignal(a, now)
+		s.wallet = wallet
+		s.checkpoint = cursor.clone()
+		signals = append(signals, s)
+	}
+	if len(signals) == 0 {
+		return nil, &cursor, nil
+	}
+	posErr := f.resolvePositions(ctx, wallet, signals)
+
+	var out []TradeSignal
+	for _, s := range signals {
+		if s.Size.LessThan(f.opts.MinSize) || s.UsdcSize.LessThan(f.opts.MinUsdcSize) {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out, &cursor, posErr
+}
+
+// resolvePositions sets the resulting position of each signal, working back
+// from the wallet's current positions in the traded markets
+func (f *Follower) resolvePositions(ctx context.Context, wallet string, signals []TradeSignal) error {
+	var markets []string
+	seen := make(map[string]bool)
+	for _, s := range signals {
+		if !seen[s.ConditionId] {
+			seen[s.ConditionId] = true
+			markets = append(markets, s.ConditionId)
+		}
+	}
+	zero := decimal.Zero
+	positions, err := f.client.GetAllPositions(ctx, &polymarketdata.GetPositionsParams{User: wallet, Market: markets, SizeThreshold: &zero})
+	if err != nil {
+		return fmt.Errorf("failed to fetch positions of %s: %w", wallet, err)
+	}
+
+	size := make(map[string]decimal.Decimal)
+	for _, p := range positions {
+		size[p.Asset] = p.Size
+	}
+	for i := len(signals) - 1; i >= 0; i-- {
+		s := &signals[i]
+		s.Position = decimal.Max(size[s.Asset], decimal.Zero)
+		s.PositionKnown = true
+		if s.Side == polymarketdata.TradeSideSell {
+			size[s.Asset] = s.Position.Add(s.Size)
+		} else {
+			size[s.Asset] = s.Position.Sub(s.Size)
+		}
+	}
+	return nil
+}
+
+func newTradeSignal(a polymarketdata.Activity, now time.Time) TradeSignal {
+	lag := now.Sub(time.Unix(a.Timestamp, 0))
+	if lag < 0 {
+		lag = 0
+	}
+	return TradeSignal{
+		ProxyWallet:     strings.ToLower(a.ProxyWallet),
+		Name:            a.UserProfile.DisplayName(),
+		ConditionId:     a.ConditionId,
+		Title:           a.Title,
+		Asset:           a.Asset,
+		OutcomeIndex:    a.OutcomeIndex,
+		Outcome:         a.Outcome,
+		Side:            a.Side,
+		Size:            a.Size,
+		Price:           a.Price,
+		UsdcSize:        a.UsdcSize,
+		TransactionHash: a.TransactionHash,
+		Timestamp:       a.Timestamp,
+		DetectedAt:      now,
+		Lag:             lag,
+		Activity:        a,
+	}
+}
+
+// Run polls until ctx is cancelled and sends every signal to signals. If ctx
+// is cancelled while sending, the checkpoints stay before the unsent signals.
+func (f *Follower) Run(ctx context.Context, signals chan<- TradeSignal) error {
+	return run(ctx, f.opts.Interval, func(ctx context.Context) error {
+		found, final, err := f.collect(ctx)
+		for _, s := range found {
+			select {
+			case signals <- s:
+				f.commit(s)
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		f.commitFinal(final)
+		return err
+	}, f.opts.OnError)
+}
+
+// Lag returns the lag metrics of a followed wallet
+func (f *Follower) Lag(wallet string) LagStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if stats, ok := f.lag[strings.ToLower(wallet)]; ok {
+		return *stats
+	}
+	return LagStats{}
+}
+
+// Checkpoint returns the newest trade time delivered for a wallet, which the next poll starts from
+func (f *Follower) Checkpoint(wallet string) (time.Time, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cursor, ok := f.cursors[strings.ToLower(wallet)]
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(cursor.start, 0), true
+}
@@ -0,0 +1,230 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func activity(wallet, tx string, side polymarketdata.TradeSide, ts int64, size int64) polymarketdata.Activity {
+	return polymarketdata.Activity{
+		ProxyWallet:     wallet,
+		Timestamp:       ts,
+		ConditionId:     "0xc",
+		Type:            polymarketdata.ActivityTypeTrade,
+		Size:            decimal.NewFromInt(size),
+		UsdcSize:        decimal.NewFromInt(size).Div(decimal.NewFromInt(2)),
+		TransactionHash: tx,
+		Price:           decimal.RequireFromString("0.5"),
+		Asset:           "yes",
+		Side:            side,
+	}
+}
+
+type fakeWallets struct {
+	mu        sync.Mutex
+	activity  map[string][]polymarketdata.Activity
+	positions map[string]decimal.Decimal
+}
+
+func (f *fakeWallets) handler(t *testing.T) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		q := r.URL.Query()
+		user := q.Get("user")
+		switch r.URL.Path {
+		case "/activity":
+			start, _ := strconv.ParseInt(q.Get("start"), 10, 64)
+			offset, _ := strconv.Atoi(q.Get("offset"))
+			var out []polymarketdata.Activity
+			for _, a := range f.activity[user] {
+				if a.Timestamp >= start {
+					out = append(out, a)
+				}
+			}
+			if offset > len(out) {
+				offset = len(out)
+			}
+			json.NewEncoder(w).Encode(out[offset:])
+		case "/positions":
+			var out []polymarketdata.Position
+			if size, ok := f.positions[user]; ok {
+				out = append(out, polymarketdata.Position{ProxyWallet: user, Asset: "yes", ConditionId: "0xc", Size: size})
+			}
+			json.NewEncoder(w).Encode(out)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}
+}
+
+func TestFollower(t *testing.T) {
+	fake := &fakeWallets{
+		activity: map[string][]polymarketdata.Activity{
+			"0xa": {
+				activity("0xa", "0x0", polymarketdata.TradeSideBuy, 50, 100), // Before Since
+				activity("0xa", "0x1", polymarketdata.TradeSideBuy, 100, 100),
+				activity("0xa", "0x2", polymarketdata.TradeSideSell, 130, 40),
+			},
+			"0xb": {
+				activity("0xb", "0x3", polymarketdata.TradeSideBuy, 120, 10),
+				activity("0xb", "0x4", polymarketdata.TradeSideBuy, 125, 1), // Below MinSize
+			},
+		},
+		positions: map[string]decimal.Decimal{"0xa": decimal.NewFromInt(160), "0xb": decimal.NewFromInt(11)},
+	}
+	client := newTestClient(t, fake.handler(t))
+
+	now := time.Unix(140, 0)
+	f, err := NewFollower(client, []string{"0xA", "0xb"}, &FollowerOptions{Since: time.Unix(100, 0), MinSize: decimal.NewFromInt(5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.now = func() time.Time { return now }
+
+	signals, err := f.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signals) != 3 {
+		t.Fatalf("expected 3 signals, got %+v", signals)
+	}
+	wantTx := []string{"0x1", "0x3", "0x2"}
+	wantPos := []int64{200, 10, 160}
+	for i, s := range signals {
+		if s.TransactionHash != wantTx[i] || !s.PositionKnown || !s.Position.Equal(decimal.NewFromInt(wantPos[i])) {
+			t.Errorf("signal %d: got %s with position %s, want %s with %d", i, s.TransactionHash, s.Position, wantTx[i], wantPos[i])
+		}
+	}
+	if signals[0].Lag != 40*time.Second {
+		t.Errorf("unexpected lag %s", signals[0].Lag)
+	}
+
+	lag := f.Lag("0xa")
+	if lag.Signals != 2 || lag.Max != 40*time.Second || lag.Mean != 25*time.Second || lag.Last != 10*time.Second {
+		t.Errorf("unexpected lag stats %+v", lag)
+	}
+
+	// Nothing new: the trade at the checkpoint is fetched again but not repeated
+	signals, err = f.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signals) != 0 {
+		t.Fatalf("expected no repeated signals, got %+v", signals)
+	}
+
+	// A trade at the checkpoint's timestamp that was not seen yet is reported
+	fake.mu.Lock()
+	fake.activity["0xa"] = append(fake.activity["0xa"], activity("0xa", "0x5", polymarketdata.TradeSideSell, 130, 60))
+	fake.positions["0xa"] = decimal.NewFromInt(100)
+	fake.mu.Unlock()
+	signals, err = f.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signals) != 1 || signals[0].TransactionHash != "0x5" || !signals[0].Position.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("expected the new trade at the checkpoint, got %+v", signals)
+	}
+	if cp, _ := f.Checkpoint("0xa"); cp.Unix() != 130 {
+		t.Errorf("unexpected checkpoint %d", cp.Unix())
+	}
+}
+
+func TestFollowerRun(t *testing.T) {
+	fake := &fakeWallets{activity: map[string][]polymarketdata.Activity{}, positions: map[string]decimal.Decimal{}}
+	client := newTestClient(t, fake.handler(t))
+	f, err := NewFollower(client, []string{"0xa"}, &FollowerOptions{Interval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	signals := make(chan TradeSignal)
+	go f.Run(ctx, signals)
+
+	// Wait for the first poll to set the checkpoint before trading
+	for {
+		if _, ok := f.Checkpoint("0xa"); ok {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	fake.mu.Lock()
+	fake.activity["0xa"] = append(fake.activity["0xa"], activity("0xa", "0x1", polymarketdata.TradeSideBuy, time.Now().Unix()+1, 10))
+	fake.mu.Unlock()
+
+	select {
+	case s := <-signals:
+		if s.TransactionHash != "0x1" {
+			t.Errorf("unexpected signal %+v", s)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for a signal")
+	}
+}
+
+func TestFollowerResumesUndelivered(t *testing.T) {
+	fake := &fakeWallets{
+		activity: map[string][]polymarketdata.Activity{
+			"0xa": {
+				activity("0xa", "0x1", polymarketdata.TradeSideBuy, 100, 10),
+				activity("0xa", "0x2", polymarketdata.TradeSideBuy, 110, 10),
+			},
+		},
+		positions: map[string]decimal.Decimal{"0xa": decimal.NewFromInt(20)},
+	}
+	client := newTestClient(t, fake.handler(t))
+	f, err := NewFollower(client, []string{"0xa"}, &FollowerOptions{Since: time.Unix(100, 0), Interval: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Run is cancelled after delivering the first signal
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan TradeSignal)
+	done := make(chan error)
+	go func() { done <- f.Run(ctx, signals) }()
+	if s := <-signals; s.TransactionHash != "0x1" {
+		t.Fatalf("unexpected signal %+v", s)
+	}
+	cancel()
+	<-done
+
+	cp, ok := f.Checkpoint("0xa")
+	if !ok || cp.Unix() != 100 {
+		t.Fatalf("expected the checkpoint at the delivered signal, got %d", cp.Unix())
+	}
+	if lag := f.Lag("0xa"); lag.Signals != 1 {
+		t.Errorf("expected lag of the delivered signal only, got %+v", lag)
+	}
+	found, err := f.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].TransactionHash != "0x2" {
+		t.Fatalf("expected the undelivered signal, got %+v", found)
+	}
+
+	// A new follower resumes from a saved checkpoint, repeating trades at it
+	f, err = NewFollower(client, []string{"0xa"}, &FollowerOptions{Checkpoints: map[string]time.Time{"0xA": cp}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err = f.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 || found[0].TransactionHash != "0x1" || found[1].TransactionHash != "0x2" {
+		t.Errorf("expected both trades from the checkpoint, got %+v", found)
+	}
+}
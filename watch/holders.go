@@ -0,0 +1,334 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// defaultHoldersLimit is the number of holders GetHolders returns per token when no Limit is set
+const defaultHoldersLimit = 100
+
+// HolderEventType represents the kind of change to a holder
+type HolderEventType string
+
+const (
+	HolderEntered   HolderEventType = "ENTERED"   // The wallet appears in the holder list
+	HolderExited    HolderEventType = "EXITED"    // The wallet is gone from the holder list
+	HolderIncreased HolderEventType = "INCREASED" // The wallet holds more tokens
+	HolderDecreased HolderEventType = "DECREASED" // The wallet holds fewer tokens
+)
+
+// HolderEvent is a change to a single wallet's holding of a token between two snapshots
+type HolderEvent struct {
+	Type              HolderEventType
+	ConditionId       string
+	Token             string
+	OutcomeIndex      int
+	ProxyWallet       string
+	Name              string // Display name of the wallet, if known
+	Before            decimal.Decimal
+	After             decimal.Decimal
+	Change            decimal.Decimal // After - Before
+	Timestamp         int64           // Time of the newer snapshot (Unix seconds)
+	PreviousTimestamp int64           // Time of the older snapshot (Unix seconds)
+	// Uncertain is set when the holder list was truncated at the limit and the wallet's
+	// amount is below its cutoff, so the wallet may have merely crossed the cutoff
+	// rather than entered or exited. The missing amount is reported as zero.
+	Uncertain bool
+}
+
+// HolderThresholds filters out small changes. Zero values mean not set.
+type HolderThresholds struct {
+	MinAmount    decimal.Decimal // Optional: Ignore wallets holding less than this before and after
+	MinChange    decimal.Decimal // Optional: Minimum absolute change for INCREASED and DECREASED
+	MinChangePct decimal.Decimal // Optional: Minimum change relative to Before for INCREASED and DECREASED, e.g. 0.1 for 10%
+}
+
+// HolderSnapshotterOptions configures a HolderSnapshotter
+type HolderSnapshotterOptions struct {
+	Limit            int                  // Optional: Holders per token, passed to GetHolders. Default 100
+	MinBalance       int                  // Optional: Passed to GetHolders
+	Interval         time.Duration        // Optional: Time between polls in Run. Default DefaultInterval
+	Thresholds       HolderThresholds     // Optional: Filters for reported changes
+	Store            polymarketdata.Store // Optional: Snapshots are saved here, and the latest stored snapshot is the baseline after a restart
+	BaselineLookback time.Duration        // Optional: Stored snapshots older than this are not used as the baseline. Default 7 days
+	OnError          func(error)          // Optional: Called with poll errors in Run
+}
+
+// HolderSnapshotter periodically snapshots the holders of markets and reports
+// the differences between consecutive snapshots
+type HolderSnapshotter struct {
+	client  *polymarketdata.Client
+	markets []string
+	opts    HolderSnapshotterOptions
+
+	mu     sync.Mutex
+	last   map[string]*polymarketdata.HoldersSnapshot // By condition ID
+	loaded bool
+	now    func() time.Time
+}
+
+// NewHolderSnapshotter creates a snapshotter for the given condition IDs
+func NewHolderSnapshotter(client *polymarketdata.Client, markets []string, opts *HolderSnapshotterOptions) (*HolderSnapshotter, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	if len(markets) == 0 {
+		return nil, fmt.Errorf("at least one market is required")
+	}
+	s := &HolderSnapshotter{
+		client:  client,
+		markets: append([]string(nil), markets...),
+		last:    make(map[string]*polymarketdata.HoldersSnapshot),
+		now:     time.Now,
+	}
+	if opts != nil {
+		s.opts = *opts
+	}
+	if s.opts.Limit == 0 {
+		s.opts.Limit = defaultHoldersLimit
+	}
+	return s, nil
+}
+
+// Poll snapshots every market once and returns the changes since the previous
+// snapshot. The first snapshot of a market only sets the baseline. A failure
+// for one market does not stop the others; all failures are returned joined.
+func (s *HolderSnapshotter) Poll(ctx context.Context) ([]HolderEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded && s.opts.Store != nil {
+		if err := s.loadBaseline(ctx); err != nil {
+			return nil, err
+		}
+	}
+	s.loaded = true
+
+	var events []HolderEvent
+	var errs []error
+	for _, market := range s.markets {
+		holders, err := s.client.GetHolders(ctx, &polymarketdata.GetHoldersParams{
+			Market:     []string{market},
+			Limit:      s.opts.Limit,
+			MinBalance: s.opts.MinBalance,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fetch holders of %s: %w", market, err))
+			continue
+		}
+
+		snapshot := &polymarketdata.HoldersSnapshot{ConditionId: market, Timestamp: s.now().Unix(), Holders: holders}
+		if s.opts.Store != nil {
+			if err := s.opts.Store.SaveHoldersSnapshot(ctx, snapshot); err != nil {
+				errs = append(errs, fmt.Errorf("failed to store holders of %s: %w", market, err))
+				continue
+			}
+		}
+
+		if prev, ok := s.last[market]; ok {
+			events = append(events, DiffHolders(prev, snapshot, s.opts.Limit, &s.opts.Thresholds)...)
+		}
+		s.last[market] = snapshot
+	}
+	return events, errors.Join(errs...)
+}
+
+// Run polls until ctx is cancelled and sends every event to events
+func (s *HolderSnapshotter) Run(ctx context.Context, events chan<- HolderEvent) error {
+	return run(ctx, s.opts.Interval, func(ctx context.Context) error {
+		found, err := s.Poll(ctx)
+		if emitErr := emit(ctx, found, events); emitErr != nil {
+			return emitErr
+		}
+		return err
+	}, s.opts.OnError)
+}
+
+// Latest returns the most recent snapshot of a market
+func (s *HolderSnapshotter) Latest(conditionId string) (*polymarketdata.HoldersSnapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot, ok := s.last[conditionId]
+	return snapshot, ok
+}
+
+// loadBaseline takes the latest stored snapshot of every market within the baseline lookback
+func (s *HolderSnapshotter) loadBaseline(ctx context.Context) error {
+	lookback := s.opts.BaselineLookback
+	if lookback <= 0 {
+		lookback = defaultBaselineLookback
+	}
+	start := max(s.now().Unix()-int64(lookback/time.Second), 1)
+	for _, market := range s.markets {
+		snapshots, err := s.opts.Store.QueryHoldersSnapshots(ctx, &polymarketdata.StoreQuery{
+			ConditionId: market,
+			Start:       start,
+			Descending:  true,
+			Limit:       1,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load holders snapshot of %s: %w", market, err)
+		}
+		if len(snapshots) > 0 {
+			s.last[market] = &snapshots[0]
+		}
+	}
+	return nil
+}
+
+// DiffHolders compares two snapshots of the same market. Limit is the Limit the
+// snapshots were fetched with, used to detect truncated lists; 0 means the default.
+// Events are sorted by token, then by absolute change, largest first.
+func DiffHolders(prev, next *polymarketdata.HoldersSnapshot, limit int, th *HolderThresholds) []HolderEvent {
+	if limit <= 0 {
+		limit = defaultHoldersLimit
+	}
+	if th == nil {
+		th = &HolderThresholds{}
+	}
+
+	before := indexHolders(prev, limit)
+	after := indexHolders(next, limit)
+
+	tokens := make(map[string]bool)
+	for token := range before {
+		tokens[token] = true
+	}
+	for token := range after {
+		tokens[token] = true
+	}
+
+	var events []HolderEvent
+	for token := range tokens {
+		b, a := before[token], after[token]
+		wallets := make(map[string]bool)
+		if b != nil {
+			for w := range b.holders {
+				wallets[w] = true
+			}
+		}
+		if a != nil {
+			for w := range a.holders {
+				wallets[w] = true
+			}
+		}
+
+		for w := range wallets {
+			var old, cur *polymarketdata.Holder
+			if b != nil {
+				old = b.holders[w]
+			}
+			if a != nil {
+				cur = a.holders[w]
+			}
+
+			e := HolderEvent{
+				ConditionId:       next.ConditionId,
+				Token:             token,
+				ProxyWallet:       w,
+				Timestamp:         next.Timestamp,
+				PreviousTimestamp: prev.Timestamp,
+			}
+			ref := cur
+			if ref == nil {
+				ref = old
+			}
+			e.OutcomeIndex = ref.OutcomeIndex
+			e.ProxyWallet = ref.ProxyWallet
+			e.Name = ref.DisplayName()
+			if old != nil {
+				e.Before = old.Amount
+			}
+			if cur != nil {
+				e.After = cur.Amount
+			}
+			e.Change = e.After.Sub(e.Before)
+
+			switch {
+			case old == nil:
+				e.Type = HolderEntered
+				e.Uncertain = b != nil && b.below(e.After)
+			case cur == nil:
+				e.Type = HolderExited
+				e.Uncertain = a != nil && a.below(e.Before)
+			case e.Change.IsPositive():
+				e.Type = HolderIncreased
+			case e.Change.IsNegative():
+				e.Type = HolderDecreased
+			default:
+				continue
+			}
+			if th.pass(e) {
+				events = append(events, e)
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Token != events[j].Token {
+			return events[i].Token < events[j].Token
+		}
+		ci, cj := events[i].Change.Abs(), events[j].Change.Abs()
+		if !ci.Equal(cj) {
+			return ci.GreaterThan(cj)
+		}
+		return events[i].ProxyWallet < events[j].ProxyWallet
+	})
+	return events
+}
+
+type holderList struct {
+	holders   map[string]*polymarketdata.Holder // By lowercase wallet
+	truncated bool
+	cutoff    decimal.Decimal // Smallest listed amount
+}
+
+// below reports whether amount could be missing from a truncated list
+func (l *holderList) below(amount decimal.Decimal) bool {
+	return l.truncated && amount.LessThanOrEqual(l.cutoff)
+}
+
+func indexHolders(snapshot *polymarketdata.HoldersSnapshot, limit int) map[string]*holderList {
+	out := make(map[string]*holderList)
+	for _, mh := range snapshot.Holders {
+		l := &holderList{
+			holders:   make(map[string]*polymarketdata.Holder, len(mh.Holders)),
+			truncated: len(mh.Holders) >= limit,
+		}
+		for i := range mh.Holders {
+			h := &mh.Holders[i]
+			l.holders[strings.ToLower(h.ProxyWallet)] = h
+			if i == 0 || h.Amount.LessThan(l.cutoff) {
+				l.cutoff = h.Amount
+			}
+		}
+		out[mh.Token] = l
+	}
+	return out
+}
+
+func (th *HolderThresholds) pass(e HolderEvent) bool {
+	if th.MinAmount.IsPositive() && e.Before.LessThan(th.MinAmount) && e.After.LessThan(th.MinAmount) {
+		return false
+	}
+	if e.Type != HolderIncreased && e.Type != HolderDecreased {
+		return true
+	}
+	change := e.Change.Abs()
+	if th.MinChange.IsPositive() && change.LessThan(th.MinChange) {
+		return false
+	}
+	if th.MinChangePct.IsPositive() && change.LessThan(e.Before.Mul(th.MinChangePct)) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,228 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, req)
+	return rec.Result(), nil
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *polymarketdata.Client {
+	t.Helper()
+	client, err := polymarketdata.NewClient(&http.Client{Transport: handlerTransport{handler}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func holder(wallet string, amount int64) polymarketdata.Holder {
+	return polymarketdata.Holder{ProxyWallet: wallet, Asset: "yes", Amount: decimal.NewFromInt(amount), UserProfile: polymarketdata.UserProfile{Name: "name-" + wallet}}
+}
+
+func snapshot(ts int64, holders ...polymarketdata.Holder) *polymarketdata.HoldersSnapshot {
+	return &polymarketdata.HoldersSnapshot{
+		ConditionId: "0xc",
+		Timestamp:   ts,
+		Holders:     []polymarketdata.MarketHolders{{Token: "yes", Holders: holders}},
+	}
+}
+
+func TestDiffHolders(t *testing.T) {
+	prev := snapshot(100, holder("0xa", 100), holder("0xb", 50), holder("0xc", 10))
+	next := snapshot(200, holder("0xA", 150), holder("0xd", 30), holder("0xc", 11))
+
+	events := DiffHolders(prev, next, 0, &HolderThresholds{MinChange: decimal.NewFromInt(5)})
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d: %+v", len(events), events)
+	}
+
+	want := []struct {
+		typ    HolderEventType
+		wallet string
+		change int64
+	}{
+		{HolderIncreased, "0xA", 50},
+		{HolderExited, "0xb", -50},
+		{HolderEntered, "0xd", 30},
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Type != w.typ || e.ProxyWallet != w.wallet || !e.Change.Equal(decimal.NewFromInt(w.change)) {
+			t.Errorf("event %d: got %s %s %s, want %s %s %d", i, e.Type, e.ProxyWallet, e.Change, w.typ, w.wallet, w.change)
+		}
+		if e.Uncertain {
+			t.Errorf("event %d: expected a certain change", i)
+		}
+		if e.Token != "yes" || e.Timestamp != 200 || e.PreviousTimestamp != 100 {
+			t.Errorf("event %d: unexpected token or timestamps %+v", i, e)
+		}
+	}
+	if events[0].Name != "name-0xA" || !events[0].Before.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("unexpected event %+v", events[0])
+	}
+}
+
+func TestDiffHoldersTruncated(t *testing.T) {
+	prev := snapshot(100, holder("0xa", 100), holder("0xb", 50), holder("0xc", 10))
+	next := snapshot(200, holder("0xa", 100), holder("0xb", 50), holder("0xd", 20))
+
+	events := DiffHolders(prev, next, 3, nil)
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", events)
+	}
+	for _, e := range events {
+		// 0xc may still hold 10 below the cutoff of 20; 0xd may have held up to 10 before
+		if e.ProxyWallet == "0xc" && (e.Type != HolderExited || !e.Uncertain) {
+			t.Errorf("expected an uncertain exit, got %+v", e)
+		}
+		if e.ProxyWallet == "0xd" && (e.Type != HolderEntered || e.Uncertain) {
+			t.Errorf("expected a certain entry above the previous cutoff, got %+v", e)
+		}
+	}
+}
+
+func TestHolderThresholds(t *testing.T) {
+	prev := snapshot(100, holder("0xa", 100), holder("0xs", 1))
+	next := snapshot(200, holder("0xa", 105), holder("0xs", 3))
+
+	th := &HolderThresholds{MinAmount: decimal.NewFromInt(5), MinChangePct: decimal.RequireFromString("0.1")}
+	if events := DiffHolders(prev, next, 0, th); len(events) != 0 {
+		t.Errorf("expected small changes to be filtered, got %+v", events)
+	}
+	if events := DiffHolders(prev, next, 0, nil); len(events) != 2 {
+		t.Errorf("expected 2 events without thresholds, got %+v", events)
+	}
+}
+
+func TestHolderSnapshotterPoll(t *testing.T) {
+	var mu sync.Mutex
+	responses := [][]polymarketdata.MarketHolders{
+		{{Token: "yes", Holders: []polymarketdata.Holder{holder("0xa", 100)}}},
+		{{Token: "yes", Holders: []polymarketdata.Holder{holder("0xa", 100), holder("0xb", 40)}}},
+		{{Token: "yes", Holders: []polymarketdata.Holder{holder("0xb", 40)}}},
+		{{Token: "yes", Holders: []polymarketdata.Holder{holder("0xb", 50)}}},
+	}
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Query().Get("market") != "0xc" || r.URL.Query().Get("limit") != "10" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(responses[calls])
+		calls++
+	})
+
+	store, err := polymarketdata.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	ctx := context.Background()
+	opts := &HolderSnapshotterOptions{Limit: 10, Store: store}
+	s, err := NewHolderSnapshotter(client, []string{"0xc"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := int64(1000)
+	s.now = func() time.Time { ts++; return time.Unix(ts, 0) }
+
+	events, err := s.Poll(ctx)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("expected the first poll to set the baseline, got %v, %+v", err, events)
+	}
+	events, err = s.Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != HolderEntered || events[0].ProxyWallet != "0xb" {
+		t.Errorf("expected 0xb to enter, got %+v", events)
+	}
+
+	// A new snapshotter resumes from the stored baseline
+	restarted, err := NewHolderSnapshotter(client, []string{"0xc"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted.now = s.now
+	events, err = restarted.Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != HolderExited || events[0].ProxyWallet != "0xa" {
+		t.Errorf("expected 0xa to exit, got %+v", events)
+	}
+
+	stored, err := store.QueryHoldersSnapshots(ctx, &polymarketdata.StoreQuery{ConditionId: "0xc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 3 {
+		t.Errorf("expected 3 stored snapshots, got %d", len(stored))
+	}
+
+	// Snapshots older than the lookback are not a baseline
+	stale, err := NewHolderSnapshotter(client, []string{"0xc"}, &HolderSnapshotterOptions{Limit: 10, Store: store, BaselineLookback: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale.now = func() time.Time { return time.Unix(ts+7200, 0) }
+	if events, err = stale.Poll(ctx); err != nil || len(events) != 0 {
+		t.Errorf("expected the first poll to set the baseline, got %v, %+v", err, events)
+	}
+}
+
+func TestHolderSnapshotterRun(t *testing.T) {
+	amount := int64(0)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		amount += 10
+		json.NewEncoder(w).Encode([]polymarketdata.MarketHolders{{Token: "yes", Holders: []polymarketdata.Holder{holder("0xa", amount)}}})
+	})
+
+	s, err := NewHolderSnapshotter(client, []string{"0xc"}, &HolderSnapshotterOptions{Interval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan HolderEvent)
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx, events) }()
+
+	e := <-events
+	cancel()
+	if e.Type != HolderIncreased || !e.Change.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected Run to stop with context.Canceled, got %v", err)
+	}
+}
+
+func TestNewHolderSnapshotterValidation(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {})
+	if _, err := NewHolderSnapshotter(client, nil, nil); err == nil {
+		t.Error("expected an error without markets")
+	}
+	if _, err := NewHolderSnapshotter(nil, []string{"0xc"}, nil); err == nil {
+		t.Error("expected an error without a client")
+	}
+}
@@ -0,0 +1,296 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// precision is the number of decimal places kept by divisions and square roots
+const precision = 16
+
+const (
+	defaultChangeWindow = time.Hour
+	defaultZHistory     = 20
+	minZSamples         = 5
+)
+
+// OpenInterestAlertType represents the kind of open interest alert
+type OpenInterestAlertType string
+
+const (
+	OpenInterestAbove  OpenInterestAlertType = "ABOVE"  // The value rose to or past the Above threshold
+	OpenInterestBelow  OpenInterestAlertType = "BELOW"  // The value fell to or past the Below threshold
+	OpenInterestChange OpenInterestAlertType = "CHANGE" // The value moved by at least ChangePct within the window
+	OpenInterestSpike  OpenInterestAlertType = "SPIKE"  // The latest change is an outlier against recent changes
+)
+
+// OpenInterestAlert is raised when a market's open interest crosses a threshold
+type OpenInterestAlert struct {
+	Type              OpenInterestAlertType
+	Market            string
+	Before            decimal.Decimal // Value of the earlier sample
+	After             decimal.Decimal // Value of the latest sample
+	Change            decimal.Decimal // After - Before
+	ChangePct         decimal.Decimal // Change relative to Before; zero if Before is zero
+	ZScore            decimal.Decimal // Set for SPIKE
+	Threshold         decimal.Decimal // The threshold that was crossed
+	Timestamp         int64           // Time of the latest sample (Unix seconds)
+	PreviousTimestamp int64           // Time of the earlier sample (Unix seconds)
+}
+
+// OpenInterestThresholds configures the alerts of a market. Zero values mean not set.
+type OpenInterestThresholds struct {
+	Above     decimal.Decimal // Optional: Alert when the value rises to or past this
+	Below     decimal.Decimal // Optional: Alert when the value falls to or past this
+	ChangePct decimal.Decimal // Optional: Alert when the value moves by this fraction within Window, e.g. 0.2 for 20%
+	Window    time.Duration   // Optional: Window of ChangePct. Default 1h
+	ZScore    decimal.Decimal // Optional: Alert when the latest change is this many standard deviations from the mean of recent changes
+	History   int             // Optional: Number of recent changes for ZScore. Default 20
+}
+
+// OpenInterestMonitorOptions configures an OpenInterestMonitor
+type OpenInterestMonitorOptions struct {
+	Interval   time.Duration                     // Optional: Time between polls in Run. Default DefaultInterval
+	Thresholds OpenInterestThresholds            // Optional: Alerts of every market
+	Markets    map[string]OpenInterestThresholds // Optional: Per-market alerts, by condition ID, replacing Thresholds
+	Store      polymarketdata.Store              // Optional: Samples are saved here, and recent stored samples are the history after a restart
+	OnError    func(error)                       // Optional: Called with poll errors in Run
+}
+
+// OpenInterestMonitor periodically samples the open interest of markets and
+// raises alerts on thresholds, changes over a window and spikes
+type OpenInterestMonitor struct {
+	client  *polymarketdata.Client
+	markets []string
+	opts    OpenInterestMonitorOptions
+	config  map[string]OpenInterestThresholds // By lowercase condition ID
+
+	mu       sync.Mutex
+	series   map[string][]polymarketdata.OpenInterestReading // By lowercase condition ID, oldest first
+	changing map[string]bool                                 // A CHANGE alert is active until the change falls below the threshold
+	loaded   bool
+	now      func() time.Time
+}
+
+// NewOpenInterestMonitor creates a monitor for the given condition IDs
+func NewOpenInterestMonitor(client *polymarketdata.Client, markets []string, opts *OpenInterestMonitorOptions) (*OpenInterestMonitor, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	if len(markets) == 0 {
+		return nil, fmt.Errorf("at least one market is required")
+	}
+	m := &OpenInterestMonitor{
+		client:   client,
+		markets:  append([]string(nil), markets...),
+		config:   make(map[string]OpenInterestThresholds),
+		series:   make(map[string][]polymarketdata.OpenInterestReading),
+		changing: make(map[string]bool),
+		now:      time.Now,
+	}
+	if opts != nil {
+		m.opts = *opts
+	}
+	overrides := make(map[string]OpenInterestThresholds)
+	for market, th := range m.opts.Markets {
+		overrides[strings.ToLower(market)] = th
+	}
+	for _, market := range m.markets {
+		th, ok := overrides[strings.ToLower(market)]
+		if !ok {
+			th = m.opts.Thresholds
+		}
+		if th.Window <= 0 {
+			th.Window = defaultChangeWindow
+		}
+		if th.History <= 0 {
+			th.History = defaultZHistory
+		}
+		m.config[strings.ToLower(market)] = th
+	}
+	return m, nil
+}
+
+// Poll samples every market once, stores the samples and returns the alerts they raise
+func (m *OpenInterestMonitor) Poll(ctx context.Context) ([]OpenInterestAlert, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.loaded && m.opts.Store != nil {
+		if err := m.loadHistory(ctx); err != nil {
+			return nil, err
+		}
+	}
+	m.loaded = true
+
+	values, err := m.client.GetOpenInterest(ctx, &polymarketdata.GetOpenInterestParams{Market: m.markets})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch open interest: %w", err)
+	}
+
+	ts := m.now().Unix()
+	readings := make([]polymarketdata.OpenInterestReading, 0, len(values))
+	for _, v := range values {
+		if _, ok := m.config[strings.ToLower(v.Market)]; ok {
+			readings = append(readings, polymarketdata.OpenInterestReading{Timestamp: ts, OpenInterest: v})
+		}
+	}
+	if m.opts.Store != nil && len(readings) > 0 {
+		if err := m.opts.Store.SaveOpenInterest(ctx, readings); err != nil {
+			return nil, fmt.Errorf("failed to store open interest: %w", err)
+		}
+	}
+
+	var alerts []OpenInterestAlert
+	for _, r := range readings {
+		key := strings.ToLower(r.Market)
+		th := m.config[key]
+		history := m.series[key]
+		alerts = append(alerts, m.check(key, th, history, r)...)
+		m.series[key] = trimHistory(append(history, r), th, ts)
+	}
+	return alerts, nil
+}
+
+// Run polls until ctx is cancelled and sends every alert to alerts
+func (m *OpenInterestMonitor) Run(ctx context.Context, alerts chan<- OpenInterestAlert) error {
+	return run(ctx, m.opts.Interval, func(ctx context.Context) error {
+		found, err := m.Poll(ctx)
+		if emitErr := emit(ctx, found, alerts); emitErr != nil {
+			return emitErr
+		}
+		return err
+	}, m.opts.OnError)
+}
+
+// Series returns the recent samples of a market that the monitor keeps, oldest first
+func (m *OpenInterestMonitor) Series(market string) []polymarketdata.OpenInterestReading {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]polymarketdata.OpenInterestReading(nil), m.series[strings.ToLower(market)]...)
+}
+
+func (m *OpenInterestMonitor) check(key string, th OpenInterestThresholds, history []polymarketdata.OpenInterestReading, r polymarketdata.OpenInterestReading) []OpenInterestAlert {
+	if len(history) == 0 {
+		return nil
+	}
+	var alerts []OpenInterestAlert
+	prev := history[len(history)-1]
+
+	if th.Above.IsPositive() && prev.Value.LessThan(th.Above) && r.Value.GreaterThanOrEqual(th.Above) {
+		alerts = append(alerts, newOpenInterestAlert(OpenInterestAbove, prev, r, th.Above))
+	}
+	if th.Below.IsPositive() && prev.Value.GreaterThan(th.Below) && r.Value.LessThanOrEqual(th.Below) {
+		alerts = append(alerts, newOpenInterestAlert(OpenInterestBelow, prev, r, th.Below))
+	}
+
+	if th.ChangePct.IsPositive() {
+		// Compare with the earliest sample within the window
+		base := prev
+		for _, h := range history {
+			if h.Timestamp >= r.Timestamp-int64(th.Window/time.Second) {
+				base = h
+				break
+			}
+		}
+		a := newOpenInterestAlert(OpenInterestChange, base, r, th.ChangePct)
+		crossed := base.Value.IsPositive() && a.ChangePct.Abs().GreaterThanOrEqual(th.ChangePct)
+		if crossed && !m.changing[key] {
+			alerts = append(alerts, a)
+		}
+		m.changing[key] = crossed
+	}
+
+	if th.ZScore.IsPositive() && len(history) > minZSamples {
+		changes := make([]decimal.Decimal, 0, len(history)-1)
+		for i := max(1, len(history)-th.History); i < len(history); i++ {
+			changes = append(changes, history[i].Value.Sub(history[i-1].Value))
+		}
+		if z, ok := zScore(r.Value.Sub(prev.Value), changes); ok && z.Abs().GreaterThanOrEqual(th.ZScore) {
+			a := newOpenInterestAlert(OpenInterestSpike, prev, r, th.ZScore)
+			a.ZScore = z
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts
+}
+
+// loadHistory loads the stored samples of the change window, or of as many
+// intervals as the z-score history needs if that reaches further back
+func (m *OpenInterestMonitor) loadHistory(ctx context.Context) error {
+	ts := m.now().Unix()
+	interval := m.opts.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	for _, market := range m.markets {
+		key := strings.ToLower(market)
+		th := m.config[key]
+		span := max(th.Window, time.Duration(th.History+1)*interval)
+		start := max(ts-int64(span/time.Second), 1)
+		readings, err := m.opts.Store.QueryOpenInterest(ctx, &polymarketdata.StoreQuery{ConditionId: market, Start: start})
+		if err != nil {
+			return fmt.Errorf("failed to load open interest of %s: %w", market, err)
+		}
+		m.series[key] = trimHistory(readings, th, ts)
+	}
+	return nil
+}
+
+// trimHistory keeps the samples within the change window and enough samples for the z-score
+func trimHistory(readings []polymarketdata.OpenInterestReading, th OpenInterestThresholds, now int64) []polymarketdata.OpenInterestReading {
+	keep := th.History + 1
+	cutoff := now - int64(th.Window/time.Second)
+	start := 0
+	for start < len(readings)-keep && readings[start].Timestamp < cutoff {
+		start++
+	}
+	return append([]polymarketdata.OpenInterestReading(nil), readings[start:]...)
+}
+
+func newOpenInterestAlert(typ OpenInterestAlertType, before, after polymarketdata.OpenInterestReading, threshold decimal.Decimal) OpenInterestAlert {
+	a := OpenInterestAlert{
+		Type:              typ,
+		Market:            after.Market,
+		Before:            before.Value,
+		After:             after.Value,
+		Change:            after.Value.Sub(before.Value),
+		Threshold:         threshold,
+		Timestamp:         after.Timestamp,
+		PreviousTimestamp: before.Timestamp,
+	}
+	if !before.Value.IsZero() {
+		a.ChangePct = a.Change.DivRound(before.Value, precision)
+	}
+	return a
+}
+
+// zScore returns how many standard deviations x is from the mean of samples,
+// or false if the samples have no variance
+func zScore(x decimal.Decimal, samples []decimal.Decimal) (decimal.Decimal, bool) {
+	if len(samples) < 2 {
+		return decimal.Zero, false
+	}
+	n := decimal.NewFromInt(int64(len(samples)))
+	mean := decimal.Sum(samples[0], samples[1:]...).DivRound(n, precision)
+	var sq decimal.Decimal
+	for _, s := range samples {
+		d := s.Sub(mean)
+		sq = sq.Add(d.Mul(d))
+	}
+	variance := sq.DivRound(n, precision)
+	if !variance.IsPositive() {
+		return decimal.Zero, false
+	}
+	sd, err := variance.PowWithPrecision(decimal.NewFromFloat(0.5), precision)
+	if err != nil || !sd.IsPositive() {
+		return decimal.Zero, false
+	}
+	return x.Sub(mean).DivRound(sd, precision), true
+}
@@ -0,0 +1,150 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// fakeOpenInterest serves /oi with a settable value per market
+type fakeOpenInterest struct {
+	mu     sync.Mutex
+	values map[string]int64
+}
+
+func (f *fakeOpenInterest) set(market string, value int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.values[market] = value
+}
+
+func (f *fakeOpenInterest) handler(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []polymarketdata.OpenInterest
+	for market, v := range f.values {
+		out = append(out, polymarketdata.OpenInterest{Market: market, Value: decimal.NewFromInt(v)})
+	}
+	json.NewEncoder(w).Encode(out)
+}
+
+func newTestMonitor(t *testing.T, fake *fakeOpenInterest, opts *OpenInterestMonitorOptions) *OpenInterestMonitor {
+	t.Helper()
+	m, err := NewOpenInterestMonitor(newTestClient(t, fake.handler), []string{"0xa"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := int64(1000)
+	m.now = func() time.Time { ts += 60; return time.Unix(ts, 0) }
+	return m
+}
+
+func pollValues(t *testing.T, m *OpenInterestMonitor, fake *fakeOpenInterest, values ...int64) [][]OpenInterestAlert {
+	t.Helper()
+	var out [][]OpenInterestAlert
+	for _, v := range values {
+		fake.set("0xa", v)
+		alerts, err := m.Poll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, alerts)
+	}
+	return out
+}
+
+func TestOpenInterestThresholds(t *testing.T) {
+	fake := &fakeOpenInterest{values: map[string]int64{}}
+	m := newTestMonitor(t, fake, &OpenInterestMonitorOptions{
+		Thresholds: OpenInterestThresholds{Above: decimal.NewFromInt(120), Below: decimal.NewFromInt(115)},
+	})
+
+	polls := pollValues(t, m, fake, 100, 150, 160, 110)
+	if len(polls[0]) != 0 || len(polls[2]) != 0 {
+		t.Errorf("expected alerts only on crossings, got %+v", polls)
+	}
+	if len(polls[1]) != 1 || polls[1][0].Type != OpenInterestAbove || !polls[1][0].Before.Equal(decimal.NewFromInt(100)) || polls[1][0].PreviousTimestamp != 1060 || polls[1][0].Timestamp != 1120 {
+		t.Errorf("unexpected ABOVE alert %+v", polls[1])
+	}
+	if len(polls[3]) != 1 || polls[3][0].Type != OpenInterestBelow || !polls[3][0].Change.Equal(decimal.NewFromInt(-50)) {
+		t.Errorf("unexpected BELOW alert %+v", polls[3])
+	}
+}
+
+func TestOpenInterestChange(t *testing.T) {
+	fake := &fakeOpenInterest{values: map[string]int64{}}
+	m := newTestMonitor(t, fake, &OpenInterestMonitorOptions{
+		Markets: map[string]OpenInterestThresholds{"0xA": {ChangePct: decimal.RequireFromString("0.2"), Window: 3 * time.Minute}},
+	})
+
+	polls := pollValues(t, m, fake, 100, 110, 125, 126, 127, 127, 160)
+	var got []int
+	for i, alerts := range polls {
+		for _, a := range alerts {
+			if a.Type != OpenInterestChange {
+				t.Errorf("unexpected alert %+v", a)
+			}
+			got = append(got, i)
+		}
+	}
+	// 125 is 25% above 100 and the alert stays active at 126; it clears once
+	// 100 leaves the window, and 160 is 27% above 126
+	if len(got) != 2 || got[0] != 2 || got[1] != 6 {
+		t.Fatalf("expected CHANGE alerts at polls 2 and 6, got %v", got)
+	}
+	a := polls[2][0]
+	if !a.ChangePct.Equal(decimal.RequireFromString("0.25")) || a.PreviousTimestamp != 1060 {
+		t.Errorf("unexpected CHANGE alert %+v", a)
+	}
+}
+
+func TestOpenInterestSpikeAndRestart(t *testing.T) {
+	fake := &fakeOpenInterest{values: map[string]int64{}}
+	store, err := polymarketdata.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	opts := &OpenInterestMonitorOptions{
+		Thresholds: OpenInterestThresholds{ZScore: decimal.NewFromInt(3), History: 10},
+		Store:      store,
+	}
+
+	m := newTestMonitor(t, fake, opts)
+	for _, alerts := range pollValues(t, m, fake, 100, 101, 100, 101, 100, 101, 100, 101) {
+		if len(alerts) != 0 {
+			t.Fatalf("expected no alerts for noise, got %+v", alerts)
+		}
+	}
+
+	// A restarted monitor picks up the stored history
+	m = newTestMonitor(t, fake, opts)
+	m.now = func() time.Time { return time.Unix(2000, 0) }
+	polls := pollValues(t, m, fake, 120)
+	if len(polls[0]) != 1 || polls[0][0].Type != OpenInterestSpike {
+		t.Fatalf("expected a SPIKE alert, got %+v", polls[0])
+	}
+	a := polls[0][0]
+	if !a.Before.Equal(decimal.NewFromInt(101)) || !a.ZScore.GreaterThan(decimal.NewFromInt(3)) {
+		t.Errorf("unexpected SPIKE alert %+v", a)
+	}
+	if n := len(m.Series("0xA")); n != 9 {
+		t.Errorf("expected 9 samples, got %d", n)
+	}
+
+	// Samples older than the change window and the z-score history are not loaded
+	m = newTestMonitor(t, fake, opts)
+	m.now = func() time.Time { return time.Unix(2000+3600, 0) }
+	if polls := pollValues(t, m, fake, 150); len(polls[0]) != 0 {
+		t.Errorf("expected no alerts without history, got %+v", polls[0])
+	}
+	if n := len(m.Series("0xA")); n != 2 {
+		t.Errorf("expected the sample at 2000 and the new one, got %d", n)
+	}
+}
@@ -0,0 +1,355 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+// PositionEventType represents the kind of change to a position
+type PositionEventType string
+
+const (
+	PositionOpened     PositionEventType = "OPENED"     // The wallet holds a new asset
+	PositionIncreased  PositionEventType = "INCREASED"  // The wallet holds more of the asset
+	PositionReduced    PositionEventType = "REDUCED"    // The wallet holds less of the asset
+	PositionClosed     PositionEventType = "CLOSED"     // The asset is gone from the wallet's positions
+	PositionRedeemable PositionEventType = "REDEEMABLE" // The market resolved and the position can be redeemed
+	PositionMergeable  PositionEventType = "MERGEABLE"  // The wallet holds both outcomes and can merge them
+)
+
+// PositionEvent is a change to a single position of a wallet
+type PositionEvent struct {
+	Type              PositionEventType
+	ProxyWallet       string
+	Asset             string
+	ConditionId       string
+	Title             string
+	OutcomeIndex      int
+	Outcome           string
+	Before            decimal.Decimal // Size when last reported
+	After             decimal.Decimal
+	Change            decimal.Decimal         // After - Before
+	Price             decimal.Decimal         // Current price at detection; the last known price for CLOSED
+	Timestamp         int64                   // Time of the snapshot that showed the change (Unix seconds)
+	PreviousTimestamp int64                   // Time of the snapshot the position was last reported from (Unix seconds)
+	Position          polymarketdata.Position // The position at detection; the last known one for CLOSED
+}
+
+// PositionThresholds coalesces small changes. A change below the thresholds is
+// not reported, but it accumulates against the last reported size until it
+// passes them. Zero values mean not set.
+type PositionThresholds struct {
+	MinSize      decimal.Decimal // Optional: Ignore positions smaller than this before and after
+	MinChange    decimal.Decimal // Optional: Minimum absolute change for INCREASED and REDUCED
+	MinChangePct decimal.Decimal // Optional: Minimum change relative to Before for INCREASED and REDUCED, e.g. 0.1 for 10%
+}
+
+// WalletOptions configures a single wallet of a PositionWatcher
+type WalletOptions struct {
+	Thresholds    *PositionThresholds // Optional: Overrides PositionWatcherOptions.Thresholds
+	Markets       []string            // Optional: Only watch these condition IDs
+	SizeThreshold *decimal.Decimal    // Optional: Passed to GetPositions
+}
+
+// PositionWatcherOptions configures a PositionWatcher
+type PositionWatcherOptions struct {
+	Interval         time.Duration            // Optional: Time between polls in Run. Default DefaultInterval
+	Thresholds       PositionThresholds       // Optional: Filters for reported changes of every wallet
+	Wallets          map[string]WalletOptions // Optional: Per-wallet settings, by address
+	Store            polymarketdata.Store     // Optional: Snapshots are saved here, and the latest stored snapshot is the baseline after a restart
+	BaselineLookback time.Duration            // Optional: Stored snapshots older than this are not used as the baseline. Default 7 days
+	OnError          func(error)              // Optional: Called with poll errors in Run
+}
+
+// PositionWatcher periodically snapshots the positions of wallets and reports
+// changes against the last reported state of every position
+type PositionWatcher struct {
+	client    *polymarketdata.Client
+	wallets   []string
+	opts      PositionWatcherOptions
+	perWallet map[string]WalletOptions // By lowercase wallet
+
+	mu       sync.Mutex
+	reported map[string]map[string]reportedPosition // By lowercase wallet, then asset
+	latest   map[string]*polymarketdata.PositionsSnapshot
+	loaded   bool
+	now      func() time.Time
+}
+
+// reportedPosition is a position as of the last event reported for it
+type reportedPosition struct {
+	position  polymarketdata.Position
+	timestamp int64
+}
+
+// NewPositionWatcher creates a watcher for the given wallets
+func NewPositionWatcher(client *polymarketdata.Client, wallets []string, opts *PositionWatcherOptions) (*PositionWatcher, error) {
+	if client == nil {
+		return nil, fmt.Errorf("client is required")
+	}
+	if len(wallets) == 0 {
+		return nil, fmt.Errorf("at least one wallet is required")
+	}
+	w := &PositionWatcher{
+		client:    client,
+		perWallet: make(map[string]WalletOptions),
+		reported:  make(map[string]map[string]reportedPosition),
+		latest:    make(map[string]*polymarketdata.PositionsSnapshot),
+		now:       time.Now,
+	}
+	for _, wallet := range wallets {
+		w.wallets = append(w.wallets, strings.ToLower(wallet))
+	}
+	if opts != nil {
+		w.opts = *opts
+	}
+	for wallet, o := range w.opts.Wallets {
+		w.perWallet[strings.ToLower(wallet)] = o
+	}
+	return w, nil
+}
+
+// Poll snapshots every wallet once and returns the changes since the last
+// reported state. The first snapshot of a wallet only sets the baseline. A
+// failure for one wallet does not stop the others; all failures are returned joined.
+func (w *PositionWatcher) Poll(ctx context.Context) ([]PositionEvent, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.loaded && w.opts.Store != nil {
+		if err := w.loadBaseline(ctx); err != nil {
+			return nil, err
+		}
+	}
+	w.loaded = true
+
+	var events []PositionEvent
+	var errs []error
+	for _, wallet := range w.wallets {
+		o := w.perWallet[wallet]
+		positions, err := w.client.GetAllPositions(ctx, &polymarketdata.GetPositionsParams{
+			User:          wallet,
+			Market:        o.Markets,
+			SizeThreshold: o.SizeThreshold,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to fetch positions of %s: %w", wallet, err))
+			continue
+		}
+
+		snapshot := &polymarketdata.PositionsSnapshot{User: wallet, Timestamp: w.now().Unix(), Positions: positions}
+		if w.opts.Store != nil {
+			if err := w.opts.Store.SavePositionsSnapshot(ctx, snapshot); err != nil {
+				errs = append(errs, fmt.Errorf("failed to store positions of %s: %w", wallet, err))
+				continue
+			}
+		}
+
+		th := &w.opts.Thresholds
+		if o.Thresholds != nil {
+			th = o.Thresholds
+		}
+		if reported, ok := w.reported[wallet]; ok {
+			var found []PositionEvent
+			found, w.reported[wallet] = diffPositions(wallet, reported, snapshot, th)
+			events = append(events, found...)
+		} else {
+			w.reported[wallet] = baseline(snapshot)
+		}
+		w.latest[wallet] = snapshot
+	}
+	return events, errors.Join(errs...)
+}
+
+// Run polls until ctx is cancelled and sends every event to events
+func (w *PositionWatcher) Run(ctx context.Context, events chan<- PositionEvent) error {
+	return run(ctx, w.opts.Interval, func(ctx context.Context) error {
+		found, err := w.Poll(ctx)
+		if emitErr := emit(ctx, found, events); emitErr != nil {
+			return emitErr
+		}
+		return err
+	}, w.opts.OnError)
+}
+
+// Latest returns the most recent snapshot of a wallet
+func (w *PositionWatcher) Latest(wallet string) (*polymarketdata.PositionsSnapshot, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	snapshot, ok := w.latest[strings.ToLower(wallet)]
+	return snapshot, ok
+}
+
+func (w *PositionWatcher) loadBaseline(ctx context.Context) error {
+	now := w.now().Unix()
+	for _, wallet := range w.wallets {
+		latest, err := w.latestSnapshot(ctx, wallet, now)
+		if err != nil {
+			return fmt.Errorf("failed to load positions snapshot of %s: %w", wallet, err)
+		}
+		if latest != nil {
+			w.reported[wallet] = baseline(latest)
+			w.latest[wallet] = latest
+		}
+	}
+	return nil
+}
+
+// latestSnapshot returns the latest stored snapshot of a wallet within the
+// baseline lookback, or nil if there is none
+func (w *PositionWatcher) latestSnapshot(ctx context.Context, wallet string, now int64) (*polymarketdata.PositionsSnapshot, error) {
+	lookback := w.opts.BaselineLookback
+	if lookback <= 0 {
+		lookback = defaultBaselineLookback
+	}
+	snapshots, err := w.opts.Store.QueryPositionsSnapshots(ctx, &polymarketdata.StoreQuery{
+		User:       wallet,
+		Start:      max(now-int64(lookback/time.Second), 1),
+		Descending: true,
+		Limit:      1,
+	})
+	if err != nil || len(snapshots) == 0 {
+		return nil, err
+	}
+	return &snapshots[0], nil
+}
+
+func baseline(snapshot *polymarketdata.PositionsSnapshot) map[string]reportedPosition {
+	out := make(map[string]reportedPosition, len(snapshot.Positions))
+	for _, p := range snapshot.Positions {
+		out[p.Asset] = reportedPosition{position: p, timestamp: snapshot.Timestamp}
+	}
+	return out
+}
+
+// DiffPositions compares two snapshots of the same wallet. Events are sorted
+// by condition ID, then by outcome index, with flag events after size events.
+func DiffPositions(prev, next *polymarketdata.PositionsSnapshot, th *PositionThresholds) []PositionEvent {
+	events, _ := diffPositions(strings.ToLower(next.User), baseline(prev), next, th)
+	return events
+}
+
+// diffPositions compares a snapshot with the last reported positions and
+// returns the events and the new reported state. Positions whose change is
+// filtered out keep their reported size, so small changes add up.
+func diffPositions(wallet string, reported map[string]reportedPosition, next *polymarketdata.PositionsSnapshot, th *PositionThresholds) ([]PositionEvent, map[string]reportedPosition) {
+	if th == nil {
+		th = &PositionThresholds{}
+	}
+	state := make(map[string]reportedPosition, len(next.Positions))
+	var events []PositionEvent
+
+	current := make(map[string]bool, len(next.Positions))
+	for _, p := range next.Positions {
+		if !p.Size.IsPositive() {
+			continue
+		}
+		current[p.Asset] = true
+		old, existed := reported[p.Asset]
+
+		e := newPositionEvent(wallet, p, next.Timestamp)
+		e.After = p.Size
+		if existed {
+			e.Before = old.position.Size
+			e.PreviousTimestamp = old.timestamp
+		}
+		e.Change = e.After.Sub(e.Before)
+
+		switch {
+		case !existed:
+			e.Type = PositionOpened
+		case e.Change.IsPositive():
+			e.Type = PositionIncreased
+		case e.Change.IsNegative():
+			e.Type = PositionReduced
+		}
+
+		if e.Type != "" && th.pass(e) {
+			events = append(events, e)
+			state[p.Asset] = reportedPosition{position: p, timestamp: next.Timestamp}
+		} else if existed {
+			// Keep the reported size, but take the latest flags and metadata
+			kept := p
+			kept.Size = old.position.Size
+			state[p.Asset] = reportedPosition{position: kept, timestamp: old.timestamp}
+		} else {
+			// Too small to open yet
+			continue
+		}
+
+		if p.Redeemable && (!existed || !old.position.Redeemable) {
+			f := e
+			f.Type = PositionRedeemable
+			events = append(events, f)
+		}
+		if p.Mergeable && (!existed || !old.position.Mergeable) {
+			f := e
+			f.Type = PositionMergeable
+			events = append(events, f)
+		}
+	}
+
+	for asset, old := range reported {
+		if current[asset] {
+			continue
+		}
+		e := newPositionEvent(wallet, old.position, next.Timestamp)
+		e.Type = PositionClosed
+		e.Before = old.position.Size
+		e.Change = e.Before.Neg()
+		e.PreviousTimestamp = old.timestamp
+		events = append(events, e)
+	}
+
+	order := map[PositionEventType]int{PositionRedeemable: 1, PositionMergeable: 2}
+	sort.SliceStable(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if a.ConditionId != b.ConditionId {
+			return a.ConditionId < b.ConditionId
+		}
+		if a.OutcomeIndex != b.OutcomeIndex {
+			return a.OutcomeIndex < b.OutcomeIndex
+		}
+		return order[a.Type] < order[b.Type]
+	})
+	return events, state
+}
+
+func newPositionEvent(wallet string, p polymarketdata.Position, ts int64) PositionEvent {
+	return PositionEvent{
+		ProxyWallet:  wallet,
+		Asset:        p.Asset,
+		ConditionId:  p.ConditionId,
+		Title:        p.Title,
+		OutcomeIndex: p.OutcomeIndex,
+		Outcome:      p.Outcome,
+		Price:        p.CurPrice,
+		Timestamp:    ts,
+		Position:     p,
+	}
+}
+
+func (th *PositionThresholds) pass(e PositionEvent) bool {
+	if th.MinSize.IsPositive() && e.Before.LessThan(th.MinSize) && e.After.LessThan(th.MinSize) {
+		return false
+	}
+	if e.Type != PositionIncreased && e.Type != PositionReduced {
+		return true
+	}
+	change := e.Change.Abs()
+	if th.MinChange.IsPositive() && change.LessThan(th.MinChange) {
+		return false
+	}
+	if th.MinChangePct.IsPositive() && change.LessThan(e.Before.Mul(th.MinChangePct)) {
+		return false
+	}
+	return true
+}
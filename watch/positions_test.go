@@ -0,0 +1,180 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	polymarketdata "github.com/ivanzzeth/polymarket-go-data-client"
+	"github.com/shopspring/decimal"
+)
+
+func position(asset string, size int64, price string) polymarketdata.Position {
+	return polymarketdata.Position{
+		ProxyWallet: "0xw",
+		Asset:       asset,
+		ConditionId: "0x" + asset,
+		Size:        decimal.NewFromInt(size),
+		CurPrice:    decimal.RequireFromString(price),
+	}
+}
+
+func positionsSnapshot(ts int64, positions ...polymarketdata.Position) *polymarketdata.PositionsSnapshot {
+	return &polymarketdata.PositionsSnapshot{User: "0xW", Timestamp: ts, Positions: positions}
+}
+
+func TestDiffPositions(t *testing.T) {
+	redeemable := position("c", 50, "1")
+	redeemable.Redeemable = true
+	prev := positionsSnapshot(100, position("a", 100, "0.5"), position("b", 40, "0.3"), position("c", 50, "0.9"), position("d", 10, "0.2"))
+	next := positionsSnapshot(200, position("a", 130, "0.55"), position("b", 20, "0.25"), redeemable, position("e", 5, "0.7"))
+
+	events := DiffPositions(prev, next, nil)
+	want := []struct {
+		typ    PositionEventType
+		asset  string
+		change int64
+		price  string
+	}{
+		{PositionIncreased, "a", 30, "0.55"},
+		{PositionReduced, "b", -20, "0.25"},
+		{PositionRedeemable, "c", 0, "1"},
+		{PositionClosed, "d", -10, "0.2"},
+		{PositionOpened, "e", 5, "0.7"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Type != w.typ || e.Asset != w.asset || !e.Change.Equal(decimal.NewFromInt(w.change)) || !e.Price.Equal(decimal.RequireFromString(w.price)) {
+			t.Errorf("event %d: got %s %s %s @ %s, want %+v", i, e.Type, e.Asset, e.Change, e.Price, w)
+		}
+		if e.ProxyWallet != "0xw" || e.Timestamp != 200 {
+			t.Errorf("event %d: unexpected wallet or time %+v", i, e)
+		}
+	}
+	if events[0].PreviousTimestamp != 100 || events[4].PreviousTimestamp != 0 {
+		t.Errorf("unexpected previous timestamps %d and %d", events[0].PreviousTimestamp, events[4].PreviousTimestamp)
+	}
+}
+
+func TestPositionWatcherCoalesces(t *testing.T) {
+	var mu sync.Mutex
+	sizes := map[string]int64{"0xa": 100, "0xb": 100}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		user := r.URL.Query().Get("user")
+		var out []polymarketdata.Position
+		if size := sizes[user]; size > 0 {
+			out = append(out, position("x", size, "0.5"))
+		}
+		json.NewEncoder(w).Encode(out)
+	})
+
+	store, err := polymarketdata.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	ts := int64(1000)
+	newWatcher := func() *PositionWatcher {
+		w, err := NewPositionWatcher(client, []string{"0xA", "0xb"}, &PositionWatcherOptions{
+			Thresholds: PositionThresholds{MinChange: decimal.NewFromInt(10)},
+			Wallets:    map[string]WalletOptions{"0xB": {Thresholds: &PositionThresholds{MinChangePct: decimal.RequireFromString("0.5")}}},
+			Store:      store,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.now = func() time.Time { ts += 60; return time.Unix(ts, 0) }
+		return w
+	}
+	poll := func(w *PositionWatcher, a, b int64) []PositionEvent {
+		t.Helper()
+		mu.Lock()
+		sizes["0xa"], sizes["0xb"] = a, b
+		mu.Unlock()
+		events, err := w.Poll(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return events
+	}
+
+	w := newWatcher()
+	if events := poll(w, 100, 100); len(events) != 0 {
+		t.Fatalf("expected the first poll to set the baseline, got %+v", events)
+	}
+	// Changes of 6 stay below 10, and 30% stays below 50%
+	if events := poll(w, 106, 130); len(events) != 0 {
+		t.Fatalf("expected small changes to be coalesced, got %+v", events)
+	}
+	// 0xa has now moved 12 since the last report
+	events := poll(w, 112, 140)
+	if len(events) != 1 || events[0].ProxyWallet != "0xa" || !events[0].Before.Equal(decimal.NewFromInt(100)) || !events[0].Change.Equal(decimal.NewFromInt(12)) {
+		t.Fatalf("expected the accumulated change of 0xa, got %+v", events)
+	}
+
+	// A restarted watcher resumes from the stored snapshot
+	w = newWatcher()
+	events = poll(w, 112, 0)
+	if len(events) != 1 || events[0].Type != PositionClosed || events[0].ProxyWallet != "0xb" {
+		t.Fatalf("expected 0xb to be closed after a restart, got %+v", events)
+	}
+	if latest, ok := w.Latest("0xB"); !ok || len(latest.Positions) != 0 {
+		t.Errorf("unexpected latest snapshot %+v", latest)
+	}
+}
+
+func TestPositionWatcherBaselineLookback(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]polymarketdata.Position{position("x", 30, "0.5")})
+	})
+	store, err := polymarketdata.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+	for _, s := range []*polymarketdata.PositionsSnapshot{
+		positionsSnapshot(100, position("x", 10, "0.5")),
+		positionsSnapshot(90000, position("x", 20, "0.5")),
+	} {
+		if err := store.SavePositionsSnapshot(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newWatcher := func(lookback time.Duration, now int64) *PositionWatcher {
+		w, err := NewPositionWatcher(client, []string{"0xw"}, &PositionWatcherOptions{Store: store, BaselineLookback: lookback})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.now = func() time.Time { return time.Unix(now, 0) }
+		return w
+	}
+
+	// The latest snapshot within the lookback is the baseline
+	events, err := newWatcher(0, 100000).Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != PositionIncreased || !events[0].Before.Equal(decimal.NewFromInt(20)) || events[0].PreviousTimestamp != 90000 {
+		t.Fatalf("expected an increase from the latest stored snapshot, got %+v", events)
+	}
+
+	// Snapshots older than the lookback are not a baseline
+	events, err = newWatcher(time.Hour, 200000).Poll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 0 {
+		t.Errorf("expected the first poll to set the baseline, got %+v", events)
+	}
+}
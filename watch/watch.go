@@ -0,0 +1,50 @@
+// Package watch polls the data API and reports changes as typed events.
+//
+// Every watcher has a Poll method that performs a single round of requests
+// and returns the events it found, and a Run method that polls on an
+// interval until its context is cancelled.
+package watch
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultInterval is the time between polls when no interval is configured
+const DefaultInterval = 5 * time.Minute
+
+// defaultBaselineLookback is how far back a stored baseline is searched for after a restart
+const defaultBaselineLookback = 7 * 24 * time.Hour
+
+// run calls poll immediately and then on every tick until ctx is done.
+// Poll errors are passed to onError, if set, and do not stop the loop.
+func run(ctx context.Context, interval time.Duration, poll func(context.Context) error, onError func(error)) error {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := poll(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// emit sends events to a channel, stopping early if ctx is done
+func emit[T any](ctx context.Context, events []T, ch chan<- T) error {
+	for _, e := range events {
+		select {
+		case ch <- e:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}